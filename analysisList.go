@@ -18,20 +18,40 @@ type analysisListCountOp struct {
 	resp chan int
 }
 
+type analysisListStoreOp struct {
+	analysis   Analysis
+	generation int
+	resp       chan bool
+}
+
+type analysisListPersistOp struct {
+	storage Storage
+	id      []byte
+	from    int
+	resp    chan error
+}
+
 type analysisList struct {
-	analysisListAdd    chan *analysisListAddOp
-	analysisListGet    chan *analysisListGetOp
-	analysisListGetAll chan *analysisListGetAllOp
-	analysisListCount  chan *analysisListCountOp
+	analysisListAdd     chan *analysisListAddOp
+	analysisListGet     chan *analysisListGetOp
+	analysisListGetAll  chan *analysisListGetAllOp
+	analysisListCount   chan *analysisListCountOp
+	analysisListStore   chan *analysisListStoreOp
+	analysisListPersist chan *analysisListPersistOp
 }
 
 func (t *analysisList) list() {
 	var list = make([]Analysis, 0)
+	var storage Storage
+	var id []byte
 
 	for {
 		select {
 		case add := <-t.analysisListAdd:
 			added := true
+			if storage != nil {
+				added = storage.Append(id, len(list), add.analysis) == nil
+			}
 			list = append(list, add.analysis)
 			add.resp <- added
 		case get := <-t.analysisListGet:
@@ -44,10 +64,27 @@ func (t *analysisList) list() {
 			getall.resp <- all
 		case countOp := <-t.analysisListCount:
 			countOp.resp <- len(list)
+		case store := <-t.analysisListStore:
+			stored := true
+			if storage != nil {
+				stored = storage.Append(id, store.generation, store.analysis) == nil
+			}
+			store.resp <- stored
+		case persist := <-t.analysisListPersist:
+			var err error
+			for i := persist.from; i < len(list) && err == nil; i++ {
+				err = persist.storage.Append(persist.id, i, list[i])
+			}
+			if err == nil {
+				storage = persist.storage
+				id = persist.id
+			}
+			persist.resp <- err
 		}
 	}
 }
 
+// Add keeps the analysis of the next generation, also writing it to storage if there is one
 func (t *analysisList) Add(analysis Analysis) bool {
 	add := &analysisListAddOp{analysis: analysis, resp: make(chan bool)}
 	t.analysisListAdd <- add
@@ -80,6 +117,24 @@ func (t *analysisList) Count() int {
 	return val
 }
 
+// Store writes an analysis to storage, if there is one, without keeping it in the list
+func (t *analysisList) Store(analysis Analysis, generation int) bool {
+	store := &analysisListStoreOp{analysis: analysis, generation: generation, resp: make(chan bool)}
+	t.analysisListStore <- store
+	val := <-store.resp
+
+	return val
+}
+
+// Persist writes the analyses starting at the given index to the storage and then writes each one added after it
+func (t *analysisList) Persist(storage Storage, id []byte, from int) error {
+	persist := &analysisListPersistOp{storage: storage, id: id, from: from, resp: make(chan error)}
+	t.analysisListPersist <- persist
+	val := <-persist.resp
+
+	return val
+}
+
 // func (t *analysisList) Clone() *analysisList {
 // 	shadow := newAnalysisList()
 //
@@ -95,6 +150,8 @@ func newAnalysisList() *analysisList {
 	t.analysisListGet = make(chan *analysisListGetOp)
 	t.analysisListGetAll = make(chan *analysisListGetAllOp)
 	t.analysisListCount = make(chan *analysisListCountOp)
+	t.analysisListStore = make(chan *analysisListStoreOp)
+	t.analysisListPersist = make(chan *analysisListPersistOp)
 
 	go t.list()
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	"gitlab.com/hokiegeek/life"
@@ -31,6 +32,8 @@ const (
 	Stable
 	// Dead applies to a simulation where all of the living cells were wiped outP
 	Dead
+	// Emitting applies to a simulation where a periodic core keeps emitting a stream of ships
+	Emitting
 )
 
 func (t status) String() string {
@@ -43,6 +46,8 @@ func (t status) String() string {
 		return "Stable"
 	case Dead:
 		return "Dead"
+	case Emitting:
+		return "Emitting"
 	}
 
 	return "Unknown"
@@ -55,6 +60,8 @@ const (
 	Born changeType = iota
 	// Died applies to a cell which died in the current generation
 	Died
+	// Decayed applies to a dying cell which moved on to its next refractory state, or out of the last one
+	Decayed
 )
 
 func (t changeType) String() string {
@@ -63,14 +70,29 @@ func (t changeType) String() string {
 		return "Born"
 	case Died:
 		return "Died"
+	case Decayed:
+		return "Decayed"
 	}
 
 	return "Unknown"
 } // }}}
 
+// changeOf determines the kind of change of a cell which went from one state to another
+func changeOf(from, to int) changeType {
+	switch {
+	case to == stateAlive:
+		return Born
+	case from == stateAlive:
+		return Died
+	}
+	return Decayed
+}
+
 type changedLocation struct { // {{{
 	life.Location
 	Change changeType
+	From   int
+	To     int
 }
 
 func (t *changedLocation) String() string {
@@ -79,15 +101,46 @@ func (t *changedLocation) String() string {
 	buf.WriteString(t.Change.String())
 	buf.WriteString(", ")
 	buf.WriteString(t.Location.String())
+	if t.Change == Decayed || t.To > stateAlive {
+		buf.WriteString(fmt.Sprintf(", %d -> %d", t.From, t.To))
+	}
 	buf.WriteString("}")
 	return buf.String()
 } // }}}
 
+const (
+	stateDead = iota
+	stateAlive
+)
+
+// CellState is a cell along with its state, where 1 is alive and any higher state is one of the
+// refractory states a dying cell passes through under Generations rules
+type CellState struct { // {{{
+	life.Location
+	State int
+}
+
+func (t *CellState) String() string {
+	return fmt.Sprintf("%s=%d", t.Location.String(), t.State)
+} // }}}
+
 // Analysis provides the state of each analyzed generation
 type Analysis struct { // {{{
-	Status  status
-	Living  []life.Location
-	Changes []changedLocation
+	Status     status
+	Living     []life.Location
+	Dying      []CellState
+	Changes    []changedLocation
+	Symmetry   symmetry
+	Complexity Complexity
+}
+
+// States lists every cell which is not dead, the living ones first followed by the dying ones
+func (t *Analysis) States() []CellState {
+	states := make([]CellState, 0, len(t.Living)+len(t.Dying))
+	for _, loc := range t.Living {
+		states = append(states, CellState{Location: loc, State: stateAlive})
+	}
+	return append(states, t.Dying...)
 }
 
 // Clone creates a deep copy of the indicated Analysis
@@ -95,10 +148,17 @@ func (t *Analysis) Clone() *Analysis {
 	shadow := new(Analysis)
 
 	shadow.Status = t.Status
+	shadow.Symmetry = t.Symmetry
+	shadow.Complexity = t.Complexity
 
 	shadow.Living = make([]life.Location, len(t.Living))
 	copy(shadow.Living, t.Living)
 
+	if t.Dying != nil {
+		shadow.Dying = make([]CellState, len(t.Dying))
+		copy(shadow.Dying, t.Dying)
+	}
+
 	shadow.Changes = make([]changedLocation, len(t.Changes))
 	copy(shadow.Changes, t.Changes)
 
@@ -110,12 +170,24 @@ func (t *Analysis) String() string {
 	buf.WriteString("Analysis {")
 	buf.WriteString("\n\tStatus = ")
 	buf.WriteString(t.Status.String())
+	buf.WriteString("\n\tSymmetry = ")
+	buf.WriteString(t.Symmetry.String())
+	buf.WriteString("\n\tComplexity = ")
+	buf.WriteString(t.Complexity.String())
 	buf.WriteString("\n\tLiving = {")
 	for _, living := range t.Living {
 		buf.WriteString("\n\t\t")
 		buf.WriteString(living.String())
 	}
 	buf.WriteString("\n\t}")
+	if len(t.Dying) > 0 {
+		buf.WriteString("\n\tDying = {")
+		for _, dying := range t.Dying {
+			buf.WriteString("\n\t\t")
+			buf.WriteString(dying.String())
+		}
+		buf.WriteString("\n\t}")
+	}
 	buf.WriteString("\n\tChanged = {")
 	for _, change := range t.Changes {
 		buf.WriteString("\n\t\t")
@@ -128,12 +200,27 @@ func (t *Analysis) String() string {
 
 // Biologist runs a Life simulation and analysis each generation for emerging patterns
 type Biologist struct { // {{{
-	log               *log.Logger
-	ID                []byte
-	Life              *life.Life
-	analyses          *analysisList
-	stabilityDetector *stabilityDetector
-	stopAnalysis      func()
+	log                *log.Logger
+	ID                 []byte
+	Life               Simulation
+	options            Options
+	seed               []life.Location
+	offset             int
+	limit              int
+	rules              Rules
+	analyses           *analysisList
+	stabilityDetector  *stabilityDetector
+	methuselahDetector *methuselahDetector
+	emitterDetector    *emitterDetector
+	growthClassifier   *growthClassifier
+	predictor          Predictor
+	storage            Storage
+	running            bool
+	mutex              sync.RWMutex
+	stopAnalysis       func()
+	analyzing          chan struct{}
+	done               chan struct{}
+	finish             sync.Once
 }
 
 // Analysis returns the completed analysis of the indicated generation
@@ -144,8 +231,8 @@ func (t *Biologist) Analysis(generation int) *Analysis {
 	if generation < t.analyses.Count() {
 		analysis := t.analyses.Get(generation)
 		return &analysis
-	} else if t.stabilityDetector.Detected {
-		cycleGen := t.stabilityDetector.CycleStart + ((generation - t.stabilityDetector.CycleStart) % t.stabilityDetector.CycleLength)
+	} else if detected, start, length := t.cycle(); detected {
+		cycleGen := start + ((generation - start) % length)
 		// t.log.Printf("Stable generation '%d' translated to cycle generation '%d'\n", generation, cycleGen)
 
 		stableAnalysis := new(Analysis)
@@ -157,36 +244,193 @@ func (t *Biologist) Analysis(generation int) *Analysis {
 	return nil
 }
 
-func (t *Biologist) calculateChanges(generation *life.Generation, previousLiving *[]life.Location) []changedLocation {
-	changes := make([]changedLocation, 0)
+// cycle returns whether the analysis found a stable cycle along with the generation it starts at and its length
+func (t *Biologist) cycle() (bool, int, int) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
 
-	// Add any new cells
-	for _, newCell := range generation.Living {
-		found := false
-		for _, oldCell := range *previousLiving {
-			if oldCell.Equals(&newCell) {
-				found = true
-				break
-			}
-		}
+	return t.stabilityDetector.Detected, t.stabilityDetector.CycleStart, t.stabilityDetector.CycleLength
+}
+
+// Lifespan returns a summary of how the seed evolved, or nil if the analysis has not stabilized or died yet
+func (t *Biologist) Lifespan() *Lifespan {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if !t.methuselahDetector.Finished {
+		return nil
+	}
+
+	lifespan := new(Lifespan)
+
+	lifespan.ID = t.ID
+	lifespan.Seed = make([]life.Location, len(t.seed))
+	copy(lifespan.Seed, t.seed)
+	lifespan.InitialPopulation = t.methuselahDetector.InitialPopulation
+	lifespan.PeakPopulation = t.methuselahDetector.PeakPopulation
+	lifespan.PeakGeneration = t.methuselahDetector.PeakGeneration
+	lifespan.Generations = t.methuselahDetector.Lifespan
+	lifespan.Census = t.methuselahDetector.Census
+	lifespan.Methuselah = t.methuselahDetector.Detected
+
+	return lifespan
+}
+
+// Emission returns the details of the ships being emitted, or nil if no emitter has been detected
+func (t *Biologist) Emission() *Emission {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if !t.emitterDetector.Detected {
+		return nil
+	}
+
+	emission := new(Emission)
+	*emission = t.emitterDetector.Emission
+
+	return emission
+}
+
+// Growth returns the latest classification of the trajectory of the analysis
+func (t *Biologist) Growth() Growth {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.growthClassifier.Growth
+}
 
-		if !found {
-			changes = append(changes, changedLocation{Location: newCell, Change: Born})
+// SymmetryChanges returns each of the analyzed generations in which the symmetry of the living cells was different from the previous generation
+func (t *Biologist) SymmetryChanges() []SymmetryChange {
+	changes := make([]SymmetryChange, 0)
+
+	analyses := t.analyses.GetAll()
+	for i := 1; i < len(analyses); i++ {
+		if analyses[i].Symmetry != analyses[i-1].Symmetry {
+			changes = append(changes, SymmetryChange{Generation: i, From: analyses[i-1].Symmetry, To: analyses[i].Symmetry})
 		}
 	}
 
-	// Add any cells which died
-	for _, oldCell := range *previousLiving {
-		found := false
-		for _, newCell := range generation.Living {
-			if newCell.Equals(&oldCell) {
-				found = true
-				break
-			}
+	return changes
+}
+
+// SetPredictor replaces the model used to predict how the run turns out
+func (t *Biologist) SetPredictor(predictor Predictor) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.predictor = predictor
+}
+
+// Prediction estimates how the run will turn out based on the generations leading up to the given one
+func (t *Biologist) Prediction(generation int) *Prediction {
+	if generation < 0 || generation >= t.analyses.Count() {
+		return nil
+	}
+
+	dims := t.Life.Dimensions()
+	_, area := t.extent()
+	id := fmt.Sprintf("%x", t.ID)
+	run := RunFeatures{ID: id, Width: dims.Width, Height: dims.Height, InitialPopulation: len(t.seed)}
+
+	start := generation - predictorWindow + 1
+	if start < 0 {
+		start = 0
+	}
+	recent := make([]GenerationFeatures, 0)
+	for gen := start; gen <= generation; gen++ {
+		analysis := t.analyses.Get(gen)
+		recent = append(recent, newGenerationFeatures(id, gen, &analysis, area.Width*area.Height, ""))
+	}
+
+	t.mutex.RLock()
+	predictor := t.predictor
+	t.mutex.RUnlock()
+
+	prediction := predictor.Predict(run, recent)
+	return &prediction
+}
+
+// Bounds returns the corners of the area covered by the board. On an unbounded board, the area grows beyond
+// the dimensions it was created with to include every cell which has lived
+func (t *Biologist) Bounds() (life.Location, life.Location) {
+	if board, ok := t.Life.(*board); ok {
+		return board.bounds()
+	}
+	dims := t.Life.Dimensions()
+	return life.Location{X: 0, Y: 0}, life.Location{X: dims.Width - 1, Y: dims.Height - 1}
+}
+
+// extent returns the top-left corner and the size of the area covered by the board
+func (t *Biologist) extent() (life.Location, life.Dimensions) {
+	min, max := t.Bounds()
+	return min, life.Dimensions{Width: max.X - min.X + 1, Height: max.Y - min.Y + 1}
+}
+
+// Options returns the options the board was created with
+func (t *Biologist) Options() Options {
+	return t.options
+}
+
+// geometry describes how the cells of the board are connected
+func (t *Biologist) geometry() geometry {
+	return geometry{dims: t.Life.Dimensions(), topology: t.options.Topology, offsets: t.rules.offsets()}
+}
+
+// census finds the objects among the living cells, following them across the edges of the board if they wrap
+func (t *Biologist) census(living []life.Location) Census {
+	return takeCensusOn(living, t.geometry())
+}
+
+// Census returns the objects found in the indicated generation, or nil if it has not been analyzed
+func (t *Biologist) Census(generation int) *Census {
+	analysis := t.Analysis(generation)
+	if analysis == nil {
+		return nil
+	}
+	census := t.census(analysis.Living)
+	return &census
+}
+
+// Rules returns the rules the simulation is evolving under
+func (t *Biologist) Rules() Rules {
+	return t.rules
+}
+
+// Seed returns the living cells the simulation started with
+func (t *Biologist) Seed() []life.Location {
+	seed := make([]life.Location, len(t.seed))
+	copy(seed, t.seed)
+	return seed
+}
+
+// SeedHash returns a hash of the canonical form of the seed which is shared by any of its translations, rotations and reflections
+func (t *Biologist) SeedHash() string {
+	return CanonicalHash(t.seed)
+}
+
+// calculateChanges lists the cells whose state differs from the previous generation, starting with those
+// which are not dead now followed by those which were not dead before
+func (t *Biologist) calculateChanges(current, previous *Analysis) []changedLocation {
+	changes := make([]changedLocation, 0)
+
+	before := make(map[life.Location]int)
+	for _, cell := range previous.States() {
+		before[cell.Location] = cell.State
+	}
+	after := make(map[life.Location]int)
+	for _, cell := range current.States() {
+		after[cell.Location] = cell.State
+	}
+
+	for _, cell := range current.States() {
+		if from := before[cell.Location]; from != cell.State {
+			changes = append(changes, changedLocation{Location: cell.Location, Change: changeOf(from, cell.State), From: from, To: cell.State})
 		}
+	}
 
-		if !found {
-			changes = append(changes, changedLocation{Location: oldCell, Change: Died})
+	for _, cell := range previous.States() {
+		if _, found := after[cell.Location]; !found {
+			changes = append(changes, changedLocation{Location: cell.Location, Change: changeOf(cell.State, stateDead), From: cell.State, To: stateDead})
 		}
 	}
 
@@ -194,6 +438,11 @@ func (t *Biologist) calculateChanges(generation *life.Generation, previousLiving
 }
 
 func (t *Biologist) analyze(generation *life.Generation) status {
+	return t.analyzeStates(generation, nil)
+}
+
+// analyzeStates analyzes a generation whose dying cells may still be passing through refractory states
+func (t *Biologist) analyzeStates(generation *life.Generation, dying []CellState) status {
 	var analysis Analysis
 
 	// Assume active status
@@ -203,56 +452,209 @@ func (t *Biologist) analyze(generation *life.Generation) status {
 	analysis.Living = make([]life.Location, len(generation.Living))
 	copy(analysis.Living, generation.Living)
 
-	if len(analysis.Living) == 0 {
+	if len(dying) > 0 {
+		analysis.Dying = make([]CellState, len(dying))
+		copy(analysis.Dying, dying)
+	}
+
+	if len(analysis.Living) == 0 && len(analysis.Dying) == 0 {
 		analysis.Status = Dead
 	}
 
+	analysis.Symmetry = symmetryOf(analysis.Living)
+	origin, dims := t.extent()
+	analysis.Complexity = complexityOf(origin, dims, analysis.Living)
+
 	// Initialize and start processing the living cells
 	if generation.Num <= 0 { // Special case to reduce code duplication
-		for _, loc := range generation.Living {
-			analysis.Changes = append(analysis.Changes, changedLocation{Location: loc, Change: Born})
-		}
+		analysis.Changes = t.calculateChanges(&analysis, &Analysis{})
 	} else {
-		analysis.Changes = t.calculateChanges(generation, &t.Analysis(generation.Num-1).Living)
+		analysis.Changes = t.calculateChanges(&analysis, t.Analysis(generation.Num-1))
 	}
 
+	return t.process(&analysis, generation.Num)
+}
+
+// process runs the analysis of a generation through each of the detectors and keeps it
+func (t *Biologist) process(analysis *Analysis, generation int) status {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	// Detect when cycle goes stable
-	if !t.stabilityDetector.Detected && t.stabilityDetector.analyze(&analysis, generation.Num) {
-		t.log.Printf("Found generation %d repeats stable cycle starting at %d\n", generation.Num, t.stabilityDetector.CycleStart)
+	if !t.stabilityDetector.Detected && t.stabilityDetector.analyze(analysis, generation) {
+		t.log.Printf("Found generation %d repeats stable cycle starting at %d\n", generation, t.stabilityDetector.CycleStart)
 		analysis.Status = Stable
+
+		// The repeated generation is not kept, but storing it allows the cycle to be found again when reopened
+		if !t.analyses.Store(*analysis, generation) {
+			t.log.Printf("ERROR: Could not store generation %d\n", generation)
+		}
 	} else {
+		// Detect when a periodic core keeps emitting ships
+		if !t.emitterDetector.Detected && t.emitterDetector.analyze(analysis, generation) {
+			t.log.Printf("Found generation %d emitting ships every %d generations\n", generation, t.emitterDetector.Emission.Period)
+			analysis.Status = Emitting
+		}
+
 		// Add analysis to list
-		// t.log.Printf("Adding analysis of generation %d\n", generation.Num)
-		t.analyses.Add(analysis)
+		// t.log.Printf("Adding analysis of generation %d\n", generation)
+		if !t.analyses.Add(*analysis) {
+			t.log.Printf("ERROR: Could not store generation %d\n", generation)
+		}
+	}
+
+	// Classify the trend of the population and area
+	t.growthClassifier.analyze(analysis, generation)
+
+	// Keep track of how long it takes the seed to settle down
+	t.methuselahDetector.analyze(analysis, generation, t.stabilityDetector.CycleStart)
+
+	if analysis.Status != Active {
+		t.finish.Do(func() { close(t.done) })
 	}
 
 	return analysis.Status
 }
 
+// Done returns a channel which is closed once the analysis finds the seed dying, stabilizing or emitting
+func (t *Biologist) Done() <-chan struct{} {
+	return t.done
+}
+
 // Start beings the Life simulation and analyzes each generation
 func (t *Biologist) Start() {
-	updates := make(chan *life.Generation)
-	t.stopAnalysis = t.Life.Start(updates)
+	if !t.Active() {
+		return
+	}
+	t.setRunning(true)
+
+	// Only one of the channels is used, depending on whether the simulation keeps track of dying cells
+	var updates chan *life.Generation
+	var stateUpdates chan *stateGeneration
+	var stopSimulation func()
+	if simulation, ok := t.Life.(multiStateSimulation); ok {
+		stateUpdates = make(chan *stateGeneration)
+		stopSimulation = simulation.startStates(stateUpdates)
+	} else {
+		updates = make(chan *life.Generation)
+		stopSimulation = t.Life.Start(updates)
+	}
+
+	stopped := make(chan struct{})
+	var once sync.Once
+	t.stopAnalysis = func() {
+		stopSimulation()
+		once.Do(func() { close(stopped) })
+	}
+
+	analyzing := make(chan struct{})
+	t.analyzing = analyzing
 
 	go func() {
+		defer close(analyzing)
 		for {
 			select {
+			case <-stopped:
+				return
 			case gen := <-updates:
-				// t.log.Printf("Generation %d\n", gen.Num)
-				// t.log.Printf("\n%s\n", t.Life)
-
-				// if status is !Active, then stop processing updates as there is no need
-				if status := t.analyze(gen); status != Active {
-					t.Stop()
-				}
+				t.analyzeUpdate(gen, nil)
+			case gen := <-stateUpdates:
+				t.analyzeUpdate(&gen.Generation, gen.Dying)
 			}
 		}
 	}()
 }
 
+// analyzeUpdate analyzes a generation sent by the running simulation
+func (t *Biologist) analyzeUpdate(gen *life.Generation, dying []CellState) {
+	// A resumed simulation counts its generations from the last one analyzed
+	gen = &life.Generation{Num: gen.Num + t.offset, Living: gen.Living}
+	if !t.Active() || (t.limit > 0 && gen.Num > t.limit) {
+		// A generation may still arrive after the analysis stopped itself
+		return
+	}
+
+	// t.log.Printf("Generation %d\n", gen.Num)
+	// t.log.Printf("\n%s\n", t.Life)
+
+	// if status is !Active, then stop processing updates as there is no need
+	if status := t.analyzeStates(gen, dying); status != Active || gen.Num == t.limit {
+		t.Stop()
+	}
+}
+
 // Stop ends the analysis and simulation
 func (t *Biologist) Stop() {
-	t.stopAnalysis()
+	if t.stopAnalysis != nil {
+		t.stopAnalysis()
+	}
+	t.setRunning(false)
+}
+
+// setRunning records whether the analysis is running, so that it is only resumed after a restart if it was
+func (t *Biologist) setRunning(running bool) {
+	t.mutex.Lock()
+	changed := t.running != running
+	t.running = running
+	storage := t.storage
+	t.mutex.Unlock()
+
+	if changed && storage != nil {
+		if err := storage.Update(t.runRecord()); err != nil {
+			t.log.Printf("ERROR: Could not record whether the analysis is running: %s\n", err)
+		}
+	}
+}
+
+// isRunning returns true if the analysis was started and has not been stopped since
+func (t *Biologist) isRunning() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.running
+}
+
+func (t *Biologist) runRecord() RunRecord {
+	return RunRecord{ID: t.ID, Dims: t.Life.Dimensions(), Rules: t.Rules(), Options: t.options, Seed: t.seed, Running: t.isRunning()}
+}
+
+// wait blocks until the analysis of the generation in progress, if any, is done after stopping
+func (t *Biologist) wait() {
+	if t.analyzing != nil {
+		<-t.analyzing
+	}
+}
+
+// Active returns true if the analysis has not yet found the seed dying or stabilizing
+func (t *Biologist) Active() bool {
+	if detected, _, _ := t.cycle(); detected || t.analyses.Count() == 0 {
+		return false
+	}
+	return t.analyses.Get(t.analyses.Count()-1).Status == Active
+}
+
+// Persist writes the biologist and all of its analyses to the storage, as well as every analysis which follows
+func (t *Biologist) Persist(storage Storage) error {
+	t.mutex.Lock()
+	t.storage = storage
+	t.mutex.Unlock()
+
+	if err := storage.Create(t.runRecord()); err != nil {
+		return err
+	}
+
+	if err := t.analyses.Persist(storage, t.ID, 0); err != nil {
+		return err
+	}
+
+	// The generation which repeated the cycle is not kept in the list
+	if detected, start, length := t.cycle(); detected {
+		generation := start + length
+		if err := storage.Append(t.ID, generation, *t.Analysis(generation)); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (t *Biologist) String() string {
@@ -265,32 +667,77 @@ func (t *Biologist) String() string {
 	return buf.String()
 } // }}}
 
-// New creates a new biologist with the indicated life board dimension, seed and ruleset
-func New(dims life.Dimensions, seed func(life.Dimensions, life.Location) []life.Location, rulesTester func(int, bool) bool) (*Biologist, error) {
+// create builds a biologist with the given ID without analyzing any generations
+func create(id []byte, dims life.Dimensions, seed func(life.Dimensions, life.Location) []life.Location, rules Rules, opts Options) (*Biologist, error) {
 	b := new(Biologist)
 
-	var err error
-	b.Life, err = life.New(
-		dims,
-		life.NeighborsAll,
-		seed,
-		rulesTester,
-		life.SimultaneousProcessor)
-	if err != nil {
-		log.Printf("ERROR: %s\n", err)
-		return nil, err
+	if opts.Topology == EngineTopology && rules.engineSupported() {
+		neighbors := life.NeighborsAll
+		if rules.Neighborhood == VonNeumann {
+			neighbors = life.NeighborsOrthogonal
+		}
+		engine, err := life.New(
+			dims,
+			neighbors,
+			seed,
+			rules.Tester(),
+			life.SimultaneousProcessor)
+		if err != nil {
+			log.Printf("ERROR: %s\n", err)
+			return nil, err
+		}
+		b.Life = engine
+		b.seed = engine.Seed
+	} else {
+		// The board steps in for neighborhoods the engine lacks, walled in as the engine would be
+		topology := opts.Topology
+		if topology == EngineTopology {
+			topology = Bounded
+		}
+		board, err := newBoard(dims, seed, rules, topology)
+		if err != nil {
+			log.Printf("ERROR: %s\n", err)
+			return nil, err
+		}
+		b.Life = board
+		b.seed = board.Seed
 	}
 
-	b.ID = uniqueID()
+	b.rules = rules
+	b.options = opts
+
+	b.ID = id
 	b.log = log.New(os.Stdout, fmt.Sprintf("[biologist-%x] ", b.ID), 0)
 
+	b.done = make(chan struct{})
 	b.analyses = newAnalysisList()
 	b.stabilityDetector = newStabilityDetector()
+	b.methuselahDetector = newMethuselahDetector()
+	b.methuselahDetector.takeCensus = b.census
+	b.emitterDetector = newEmitterDetector()
+	b.emitterDetector.takeCensus = b.census
+	b.growthClassifier = newGrowthClassifier()
+	b.predictor = NewLogisticRegression()
+
+	return b, nil
+}
+
+// NewWithOptions creates a new biologist with the indicated life board dimension, seed, ruleset and board options
+func NewWithOptions(dims life.Dimensions, seed func(life.Dimensions, life.Location) []life.Location, rules Rules, opts Options) (*Biologist, error) {
+	b, err := create(uniqueID(), dims, seed, rules, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate first analysis (for generation 0 / the seed)
-	b.analyze(&life.Generation{Living: b.Life.Seed, Num: 0})
+	b.analyze(&life.Generation{Living: b.seed, Num: 0})
 
 	return b, nil
 }
 
+// New creates a new biologist with the indicated life board dimension, seed and ruleset, leaving the edges of the board up to the life engine
+func New(dims life.Dimensions, seed func(life.Dimensions, life.Location) []life.Location, rulesTester func(int, bool) bool) (*Biologist, error) {
+	return NewWithOptions(dims, seed, rulesOf(rulesTester), Options{})
+}
+
 // vim: set foldmethod=marker:
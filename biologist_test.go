@@ -20,7 +20,7 @@ func TestBiologistCreate(t *testing.T) { // {{{
 		t.Fatalf("Unable to create biologist: %s\n", err)
 	}
 
-	if len(biologist.Life.Seed) <= 0 {
+	if len(biologist.Seed()) <= 0 {
 		t.Error("Created biologist with an empty seed")
 	}
 }
@@ -127,6 +127,55 @@ func TestBiologistAnalysisError(t *testing.T) {
 	// }
 } // }}}
 
+func TestBiologistGenerations(t *testing.T) { // {{{
+	// Under Brian's Brain a domino dies into the refractory state as it gives birth on either side
+	rules, err := ParseRules("B2/S/3")
+	if err != nil {
+		t.Fatalf("Unable to parse rules: %s\n", err)
+	}
+
+	size := life.Dimensions{Width: 12, Height: 12}
+	seed := []life.Location{{X: 5, Y: 5}, {X: 6, Y: 5}}
+	biologist, err := NewWithOptions(size, Pattern(seed), rules, Options{})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	runFor(biologist, 2)
+
+	first := biologist.Analysis(1)
+	if len(first.Living) != 4 || len(first.Dying) != 2 {
+		t.Fatalf("Expected 4 living and 2 dying cells but found %d and %d\n", len(first.Living), len(first.Dying))
+	}
+	transitions := make(map[changeType]int)
+	for _, change := range first.Changes {
+		transitions[change.Change]++
+		if change.Change == Died && change.To != 2 {
+			t.Errorf("Cell %s died into state %d instead of 2\n", change.Location.String(), change.To)
+		}
+	}
+	if transitions[Born] != 4 || transitions[Died] != 2 {
+		t.Errorf("Unexpected changes in generation 1: %v\n", first.Changes)
+	}
+
+	second := biologist.Analysis(2)
+	decayed := 0
+	for _, change := range second.Changes {
+		if change.Change == Decayed && change.From == 2 && change.To == 0 {
+			decayed++
+		}
+	}
+	if decayed != 2 {
+		t.Errorf("Expected the 2 dying cells to decay but found %d\n", decayed)
+	}
+	for _, cell := range second.Dying {
+		for _, loc := range seed {
+			if cell.Location.Equals(&loc) {
+				t.Errorf("Cell %s is still dying after its last refractory state\n", loc.String())
+			}
+		}
+	}
+} // }}}
+
 func TestStatusString(t *testing.T) {
 	var status status
 
@@ -149,6 +198,11 @@ func TestStatusString(t *testing.T) {
 	if len(status.String()) <= 0 {
 		t.Error("Unexpectedly retrieved empty string from status object")
 	}
+
+	status = Emitting
+	if len(status.String()) <= 0 {
+		t.Error("Unexpectedly retrieved empty string from status object")
+	}
 }
 
 // vim: set foldmethod=marker:
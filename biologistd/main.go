@@ -2,7 +2,10 @@ package main
 
 import (
 	"bytes"
+	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -10,10 +13,19 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"gitlab.com/hokiegeek/biologist"
+	"gitlab.com/hokiegeek/biologist/render"
 	"gitlab.com/hokiegeek/life"
+
+	_ "github.com/mattn/go-sqlite3"
 )
 
 /////////////////////////////////// CREATE ANALYSIS ///////////////////////////////////
@@ -45,10 +57,13 @@ const (
 
 // CreateAnalysisRequest encapsulates the needed initial data for starting a life simulation
 type CreateAnalysisRequest struct { // {{{
-	Dims    life.Dimensions
-	pattern patternType
-	Seed    []life.Location
-	// life.Rules
+	Dims     life.Dimensions
+	pattern  patternType
+	Seed     []life.Location
+	RLE      string
+	Pattern  string
+	Rules    string
+	Topology string
 }
 
 func (t *CreateAnalysisRequest) String() string {
@@ -59,6 +74,29 @@ func (t *CreateAnalysisRequest) String() string {
 	return buf.String()
 }
 
+// fits returns true if the bounding box of the cells is no larger than the board
+func fits(cells []life.Location, dims life.Dimensions) bool {
+	if len(cells) == 0 {
+		return true
+	}
+	min, max := cells[0], cells[0]
+	for _, loc := range cells {
+		if loc.X < min.X {
+			min.X = loc.X
+		}
+		if loc.Y < min.Y {
+			min.Y = loc.Y
+		}
+		if loc.X > max.X {
+			max.X = loc.X
+		}
+		if loc.Y > max.Y {
+			max.Y = loc.Y
+		}
+	}
+	return max.X-min.X < dims.Width && max.Y-min.Y < dims.Height
+}
+
 func createAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1048576))
 	if err != nil {
@@ -77,6 +115,62 @@ func createAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWrit
 	} else {
 		// log.Printf("Received create request: %s\n", req.String())
 
+		// Seeds in the RLE or Life 1.05 formats also carry the rules they evolve under. Parsed seeds are
+		// placed in the middle of the board since their coordinates are often centered on the origin
+		rules := biologist.ConwayRules()
+		if len(req.RLE) > 0 {
+			seed, rleRules, err := biologist.ReadRLE(strings.NewReader(req.RLE))
+			if err != nil {
+				log.Printf("ERROR: Could not parse RLE seed: %s\n", err)
+				postJSON(w, 422, err)
+				return
+			}
+			if !fits(seed, req.Dims) {
+				http.Error(w, "seed does not fit on the board", 422)
+				return
+			}
+			req.Seed = biologist.Center(seed, req.Dims)
+			rules = rleRules
+		} else if len(req.Pattern) > 0 {
+			seed, patternRules, err := biologist.ReadPattern(strings.NewReader(req.Pattern))
+			if err != nil {
+				log.Printf("ERROR: Could not parse pattern seed: %s\n", err)
+				postJSON(w, 422, err)
+				return
+			}
+			if !fits(seed, req.Dims) {
+				http.Error(w, "seed does not fit on the board", 422)
+				return
+			}
+			req.Seed = biologist.Center(seed, req.Dims)
+			rules = patternRules
+		}
+
+		// Rules given explicitly win over those carried by the seed
+		if len(req.Rules) > 0 {
+			if rules, err = biologist.ParseRules(req.Rules); err != nil {
+				log.Printf("ERROR: Could not parse rules: %s\n", err)
+				postJSON(w, 422, err)
+				return
+			}
+		}
+
+		var opts biologist.Options
+		if opts.Topology, err = biologist.ParseTopology(req.Topology); err != nil {
+			log.Printf("ERROR: %s\n", err)
+			postJSON(w, 422, err)
+			return
+		}
+
+		// Reuse the existing analysis if the user already submitted an equivalent seed
+		if req.pattern == USER {
+			if existing := mgr.Equivalent(req.Dims, rules, opts, req.Seed); existing != nil {
+				log.Printf("Seed is equivalent to existing analysis %x\n", existing.ID)
+				postJSON(w, http.StatusOK, newCreateAnalysisResponse(existing))
+				return
+			}
+		}
+
 		// Determine the pattern to use for seeding the board
 		var patternFunc func(life.Dimensions, life.Location) []life.Location
 		switch req.pattern {
@@ -92,7 +186,7 @@ func createAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWrit
 
 		// Create the biologist
 		// log.Printf("Creating new biologist with pattern: %v\n", patternFunc(req.Dims, life.Location{X: 0, Y: 0}))
-		biologist, err := biologist.New(req.Dims, patternFunc, life.ConwayTester())
+		biologist, err := biologist.NewWithOptions(req.Dims, patternFunc, rules, opts)
 		if err != nil {
 			panic(err)
 		}
@@ -115,6 +209,10 @@ type AnalysisUpdate struct { // {{{
 	Status     string
 	Generation int
 	Living     []life.Location
+	Dying      []biologist.CellState
+	Symmetry   string
+	Prediction *biologist.Prediction
+	Complexity biologist.Complexity
 	// Changes    []biologist.ChangedLocation
 }
 
@@ -131,9 +229,17 @@ func newAnalysisUpdate(biologist *biologist.Biologist, generation int) *Analysis
 	a.Generation = generation
 
 	a.Status = analysis.Status.String()
+	a.Complexity = analysis.Complexity
+	a.Symmetry = analysis.Symmetry.String()
+
+	// Only runs which have not yet played out need a prediction
+	if a.Status == "Active" {
+		a.Prediction = biologist.Prediction(generation)
+	}
 
 	a.Living = make([]life.Location, len(analysis.Living))
 	copy(a.Living, analysis.Living)
+	a.Dying = analysis.Dying
 
 	// a.Changes = make([]biologist.ChangedLocation, len(analysis.Changes))
 	// copy(a.Changes, analysis.Changes)
@@ -161,10 +267,15 @@ func (t *AnalysisUpdateRequest) String() string {
 	return buf.String()
 } // }}}
 
-// AnalysisUpdateResponse encapsulates the requested analysis updates
+// AnalysisUpdateResponse encapsulates the requested analysis updates along with the classifications of the
+// run as a whole, which reflect every generation analyzed so far
 type AnalysisUpdateResponse struct { // {{{
-	ID      []byte
-	Updates []AnalysisUpdate
+	ID         []byte
+	Updates    []AnalysisUpdate
+	Emission   *biologist.Emission
+	Growth     string
+	Confidence float64
+	Wolfram    string
 }
 
 func newAnalysisUpdateResponse(log *log.Logger, biologist *biologist.Biologist, startingGeneration int, maxGenerations int) *AnalysisUpdateResponse {
@@ -177,6 +288,12 @@ func newAnalysisUpdateResponse(log *log.Logger, biologist *biologist.Biologist,
 
 	r.ID = biologist.ID
 
+	r.Emission = biologist.Emission()
+	growth := biologist.Growth()
+	r.Growth = growth.Class.String()
+	r.Confidence = growth.Confidence
+	r.Wolfram = biologist.WolframClass().String()
+
 	r.Updates = make([]AnalysisUpdate, 0)
 
 	// Retrieve as many updates as are available up to the max
@@ -291,6 +408,880 @@ func controlAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWri
 	}
 }
 
+/////////////////////////////////// EXPORT ANALYSIS ///////////////////////////////////
+
+// exportAnalysis writes the living cells of a generation in the pattern format indicated by the
+// extension of the path, which is of the form /export/{id}/{generation}.{extension}
+func exportAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/export/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, err := hex.DecodeString(parts[0])
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	extension := path.Ext(parts[1])
+	generation, err := strconv.Atoi(strings.TrimSuffix(parts[1], extension))
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	b := mgr.Biologist(id)
+	if b == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	analysis := b.Analysis(generation)
+	if analysis == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var buf bytes.Buffer
+	switch extension {
+	case ".rle":
+		err = biologist.WriteRLE(&buf, analysis.Living, b.Rules())
+	case ".cells":
+		err = biologist.WriteCells(&buf, analysis.Living, fmt.Sprintf("%x generation %d", id, generation))
+	case ".lif":
+		err = biologist.WriteLife106(&buf, analysis.Living)
+	case ".mc":
+		err = biologist.WriteMacrocell(&buf, analysis.Living, b.Rules())
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format: %s", extension), 422)
+		return
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Exporting generation %d of %x as %s\n", generation, id, extension)
+
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+} // }}}
+
+/////////////////////////////////// RENDER ANALYSIS ///////////////////////////////////
+
+func queryInt(r *http.Request, name string, defaultVal int) (int, error) {
+	val := r.URL.Query().Get(name)
+	if val == "" {
+		return defaultVal, nil
+	}
+	return strconv.Atoi(val)
+}
+
+// renderAnalysis animates a range of generations. The path is of the form /render/{id} and the query
+// accepts the format (gif or apng), start generation, count of generations, cell size and delay in milliseconds
+func renderAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	id, err := hex.DecodeString(strings.TrimPrefix(r.URL.Path, "/render/"))
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	b := mgr.Biologist(id)
+	if b == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	opts := render.DefaultOptions()
+	start, err := queryInt(r, "start", 0)
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+	count, err := queryInt(r, "count", 100)
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+	if count <= 0 || count > render.MaxFrames {
+		http.Error(w, fmt.Sprintf("count must be between 1 and %d", render.MaxFrames), 422)
+		return
+	}
+	if opts.CellSize, err = queryInt(r, "cellsize", opts.CellSize); err != nil || opts.CellSize <= 0 {
+		http.Error(w, "invalid cell size", 422)
+		return
+	}
+	delay, err := queryInt(r, "delay", int(opts.Delay/time.Millisecond))
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+	opts.Delay = time.Duration(delay) * time.Millisecond
+	dims := render.Frame(b, &opts)
+	if err := opts.ValidateAnimation(dims, count); err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	analyses := render.Analyses(b, start, count)
+	if len(analyses) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	var buf bytes.Buffer
+	var contentType string
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "gif":
+		contentType = "image/gif"
+		err = render.GIF(&buf, dims, analyses, opts)
+	case "apng":
+		contentType = "image/apng"
+		err = render.APNG(&buf, dims, analyses, opts)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format: %s", format), 422)
+		return
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Rendered %d generations of %x starting at %d\n", len(analyses), id, start)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// snapshotAnalysis draws a single generation. The path is of the form /snapshot/{id}/{generation}.{png|svg}
+// and setting census=true in the query outlines each of the objects in the generation
+func snapshotAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/snapshot/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, err := hex.DecodeString(parts[0])
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	extension := path.Ext(parts[1])
+	generation, err := strconv.Atoi(strings.TrimSuffix(parts[1], extension))
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	b := mgr.Biologist(id)
+	if b == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	analysis := b.Analysis(generation)
+	if analysis == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	opts := render.DefaultOptions()
+	if opts.CellSize, err = queryInt(r, "cellsize", opts.CellSize); err != nil || opts.CellSize <= 0 {
+		http.Error(w, "invalid cell size", 422)
+		return
+	}
+	dims := render.Frame(b, &opts)
+	if err := opts.Validate(dims); err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	var census *biologist.Census
+	if r.URL.Query().Get("census") == "true" {
+		census = b.Census(generation)
+	}
+
+	var buf bytes.Buffer
+	var contentType string
+	switch extension {
+	case ".png":
+		contentType = "image/png"
+		err = render.PNG(&buf, dims, analysis, census, opts)
+	case ".svg":
+		contentType = "image/svg+xml"
+		err = render.SVG(&buf, dims, analysis, census, opts)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format: %s", extension), 422)
+		return
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Snapshot of generation %d of %x as %s\n", generation, id, extension)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+/////////////////////////////////// HEATMAP ///////////////////////////////////
+
+// heatmapAnalysis responds with the activity of each location across all analyzed generations. The path is
+// of the form /heatmap/{id} for JSON or /heatmap/{id}.png for an image of the grid selected by the kind
+// query parameter (alive, born or died)
+func heatmapAnalysis(mgr *biologist.Manager, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/heatmap/")
+	extension := path.Ext(name)
+
+	id, err := hex.DecodeString(strings.TrimSuffix(name, extension))
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	b := mgr.Biologist(id)
+	if b == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	heatmap := b.Heatmap()
+
+	switch extension {
+	case "":
+		postJSON(w, http.StatusOK, heatmap)
+	case ".png":
+		var grid [][]int
+		switch kind := r.URL.Query().Get("kind"); kind {
+		case "", "alive":
+			grid = heatmap.Alive
+		case "born":
+			grid = heatmap.Born
+		case "died":
+			grid = heatmap.Died
+		default:
+			http.Error(w, fmt.Sprintf("unknown kind: %s", kind), 422)
+			return
+		}
+
+		opts := render.DefaultOptions()
+		if opts.CellSize, err = queryInt(r, "cellsize", opts.CellSize); err != nil || opts.CellSize <= 0 {
+			http.Error(w, "invalid cell size", 422)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := render.Heatmap(&buf, grid, opts); err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format: %s", extension), 422)
+		return
+	}
+
+	log.Printf("Sent heatmap of %d generations of %x\n", heatmap.Generations, id)
+}
+
+/////////////////////////////////// SEARCH ///////////////////////////////////
+
+func queryFloat(r *http.Request, name string) (float64, error) {
+	val := r.URL.Query().Get(name)
+	if val == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(val, 64)
+}
+
+// parseCatalogQuery reads the rules, width, height, status, mindensity and maxdensity (as fractions
+// of the board), mingen and maxgen, seed hash, wolfram class and limit of a search from the query
+func parseCatalogQuery(r *http.Request) (biologist.CatalogQuery, error) {
+	var query biologist.CatalogQuery
+	var err error
+
+	values := r.URL.Query()
+	query.Rules = values.Get("rules")
+	query.Status = values.Get("status")
+	query.SeedHash = values.Get("hash")
+	query.Wolfram = values.Get("wolfram")
+
+	if query.Width, err = queryInt(r, "width", 0); err != nil {
+		return query, errors.New("invalid width")
+	}
+	if query.Height, err = queryInt(r, "height", 0); err != nil {
+		return query, errors.New("invalid height")
+	}
+	if query.MinDensity, err = queryFloat(r, "mindensity"); err != nil {
+		return query, errors.New("invalid minimum density")
+	}
+	if query.MaxDensity, err = queryFloat(r, "maxdensity"); err != nil {
+		return query, errors.New("invalid maximum density")
+	}
+	if query.MinGenerations, err = queryInt(r, "mingen", 0); err != nil {
+		return query, errors.New("invalid minimum generations")
+	}
+	if query.MaxGenerations, err = queryInt(r, "maxgen", 0); err != nil {
+		return query, errors.New("invalid maximum generations")
+	}
+	if query.Limit, err = queryInt(r, "limit", 100); err != nil {
+		return query, errors.New("invalid limit")
+	}
+
+	return query, nil
+}
+
+// searchCatalog filters the finished runs by the query
+func searchCatalog(catalog *biologist.Catalog, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	if catalog == nil {
+		http.Error(w, "no catalog configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	query, err := parseCatalogQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	entries, err := catalog.Search(query)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Printf("Found %d runs matching %v\n", len(entries), r.URL.Query())
+	postJSON(w, http.StatusOK, entries)
+}
+
+/////////////////////////////////// DATASET ///////////////////////////////////
+
+// exportDataset writes features for machine learning. The path is either of the form /dataset/{id}.npy
+// for the boards of a range of generations, or /dataset/{runs|generations}.{csv|jsonl} for the features
+// of the comma-separated ids in the query or, without any ids, of the runs matching a search of the catalog
+func exportDataset(mgr *biologist.Manager, catalog *biologist.Catalog, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/dataset/")
+	extension := path.Ext(name)
+	name = strings.TrimSuffix(name, extension)
+
+	var buf bytes.Buffer
+	var contentType string
+
+	switch extension {
+	case ".npy":
+		id, err := hex.DecodeString(name)
+		if err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		b := mgr.Biologist(id)
+		if b == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		start, err := queryInt(r, "start", 0)
+		if err != nil || start < 0 {
+			http.Error(w, "invalid start generation", 422)
+			return
+		}
+		count, err := queryInt(r, "count", 100)
+		if err != nil || count <= 0 {
+			http.Error(w, "invalid count", 422)
+			return
+		}
+
+		if err := b.WriteNPY(&buf, start, count); err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		contentType = "application/octet-stream"
+	case ".csv", ".jsonl":
+		biologists := make([]*biologist.Biologist, 0)
+		if ids := r.URL.Query().Get("ids"); ids != "" {
+			for _, hexID := range strings.Split(ids, ",") {
+				id, err := hex.DecodeString(hexID)
+				if err != nil {
+					http.Error(w, err.Error(), 422)
+					return
+				}
+				if b := mgr.Biologist(id); b != nil {
+					biologists = append(biologists, b)
+				}
+			}
+		} else {
+			if catalog == nil {
+				http.Error(w, "no catalog configured", http.StatusServiceUnavailable)
+				return
+			}
+			query, err := parseCatalogQuery(r)
+			if err != nil {
+				http.Error(w, err.Error(), 422)
+				return
+			}
+			entries, err := catalog.Search(query)
+			if err != nil {
+				panic(err)
+			}
+			for _, entry := range entries {
+				id, _ := hex.DecodeString(entry.ID)
+				if b := mgr.Biologist(id); b != nil {
+					biologists = append(biologists, b)
+				}
+			}
+		}
+
+		dataset := biologist.NewDataset(biologists)
+
+		var err error
+		switch name + extension {
+		case "runs.csv":
+			err = dataset.WriteRunsCSV(&buf)
+		case "runs.jsonl":
+			err = dataset.WriteRunsJSONL(&buf)
+		case "generations.csv":
+			err = dataset.WriteGenerationsCSV(&buf)
+		case "generations.jsonl":
+			err = dataset.WriteGenerationsJSONL(&buf)
+		default:
+			http.Error(w, fmt.Sprintf("unknown dataset: %s", name), 422)
+			return
+		}
+		if err != nil {
+			panic(err)
+		}
+
+		contentType = "text/csv"
+		if extension == ".jsonl" {
+			contentType = "application/x-ndjson"
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unsupported format: %s", extension), 422)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+
+	log.Printf("Sent dataset %s%s\n", name, extension)
+}
+
+/////////////////////////////////// EXPLORE RULES ///////////////////////////////////
+
+// ExploreRequest describes the rules to sweep and the soups to try each of them with
+type ExploreRequest struct { // {{{
+	Born           []int
+	Survive        []int
+	NotBorn        []int
+	NotSurvive     []int
+	Dims           life.Dimensions
+	Soups          int
+	Density        int
+	MaxGenerations int
+	Seed           int64
+}
+
+// ExploreResponse reports the progress of a sweep and the results of the rules with the requested behavior
+type ExploreResponse struct {
+	ID       []byte
+	Explored int
+	Total    int
+	Done     bool
+	Results  []biologist.RuleResult
+} // }}}
+
+func newExploreResponse(explorer *biologist.Explorer, behavior string) *ExploreResponse {
+	resp := new(ExploreResponse)
+
+	resp.ID = explorer.ID
+	resp.Explored, resp.Total = explorer.Progress()
+	select {
+	case <-explorer.Done():
+		resp.Done = true
+	default:
+	}
+
+	resp.Results = make([]biologist.RuleResult, 0)
+	for _, result := range explorer.Results() {
+		if behavior == "" || result.Behavior == behavior {
+			resp.Results = append(resp.Results, result)
+		}
+	}
+
+	return resp
+}
+
+// Limits on a single sweep so that a request cannot tie up the server indefinitely
+const (
+	maxExploreSide        = 256
+	maxExploreSoups       = 64
+	maxExploreGenerations = 5000
+)
+
+// exploreRules starts a sweep when posted an ExploreRequest. The path /explore/{id} reports on a sweep or
+// removes it when deleted, /explore/{id}/stop stops it and /explore/rules searches the catalog. Results can
+// be filtered by behavior
+func exploreRules(mgr *biologist.Manager, catalog *biologist.Catalog, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/explore"), "/")
+	behavior := r.URL.Query().Get("behavior")
+
+	switch {
+	case name == "" && r.Method == http.MethodPost:
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1048576))
+		if err != nil {
+			panic(err)
+		}
+		if err := r.Body.Close(); err != nil {
+			panic(err)
+		}
+
+		// Anything not specified falls back to the defaults
+		opts := biologist.DefaultExplorerOptions()
+		req := ExploreRequest{Dims: opts.Dims, Soups: opts.Soups, Density: opts.Density, MaxGenerations: opts.MaxGenerations, Seed: opts.Seed}
+		if err := json.Unmarshal(body, &req); err != nil {
+			log.Printf("ERROR: Could not handle request: %s\n", err)
+			postJSON(w, 422, err)
+			return
+		}
+		if req.Dims.Width > maxExploreSide || req.Dims.Height > maxExploreSide {
+			http.Error(w, fmt.Sprintf("the board must be at most %dx%d", maxExploreSide, maxExploreSide), 422)
+			return
+		}
+		if req.Soups > maxExploreSoups {
+			http.Error(w, fmt.Sprintf("the number of soups must be at most %d", maxExploreSoups), 422)
+			return
+		}
+		if req.MaxGenerations > maxExploreGenerations {
+			http.Error(w, fmt.Sprintf("the number of generations must be at most %d", maxExploreGenerations), 422)
+			return
+		}
+		opts.Dims, opts.Soups, opts.Density, opts.MaxGenerations, opts.Seed = req.Dims, req.Soups, req.Density, req.MaxGenerations, req.Seed
+
+		filter := biologist.RuleFilter{Born: req.Born, Survive: req.Survive, NotBorn: req.NotBorn, NotSurvive: req.NotSurvive}
+		explorer, err := biologist.NewExplorer(filter, opts)
+		if err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		mgr.AddExplorer(explorer)
+		explorer.Start()
+
+		log.Printf("Exploring rules as %x\n", explorer.ID)
+		postJSON(w, http.StatusCreated, newExploreResponse(explorer, behavior))
+	case name == "rules":
+		if catalog == nil {
+			http.Error(w, "no catalog configured", http.StatusServiceUnavailable)
+			return
+		}
+		limit, err := queryInt(r, "limit", 100)
+		if err != nil {
+			http.Error(w, "invalid limit", 422)
+			return
+		}
+		results, err := catalog.SearchRules(behavior, limit)
+		if err != nil {
+			panic(err)
+		}
+		postJSON(w, http.StatusOK, results)
+	default:
+		stop := strings.HasSuffix(name, "/stop")
+		id, err := hex.DecodeString(strings.TrimSuffix(name, "/stop"))
+		if err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+
+		explorer := mgr.Explorer(id)
+		if explorer == nil {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			mgr.RemoveExplorer(id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if stop {
+			explorer.Stop()
+		}
+
+		postJSON(w, http.StatusOK, newExploreResponse(explorer, behavior))
+	}
+}
+
+/////////////////////////////////// EVOLVE SEEDS ///////////////////////////////////
+
+// EvolveRequest describes the seeds to breed and what they are bred for
+type EvolveRequest struct { // {{{
+	Dims           life.Dimensions
+	Box            life.Dimensions
+	Rules          string
+	Objective      string
+	Period         int
+	PopulationSize int
+	Generations    int
+	MaxGenerations int
+	MutationRate   float64
+	Seed           int64
+}
+
+// EvolvedSeed is one of the best seeds along with the ID of its analysis
+type EvolvedSeed struct {
+	ID         []byte
+	Genome     []string
+	Seed       []life.Location
+	Fitness    float64
+	Generation int
+}
+
+// EvolveResponse reports the progress of an evolution and the best seeds it found
+type EvolveResponse struct {
+	ID          []byte
+	Generation  int
+	Generations int
+	Done        bool
+	Best        []EvolvedSeed
+} // }}}
+
+// newEvolveResponse reports on the evolution. The analyses of its best seeds are found through the manager
+// for as long as they remain among the best, so they are not added to it
+func newEvolveResponse(evolution *biologist.Evolution) *EvolveResponse {
+	resp := new(EvolveResponse)
+
+	resp.ID = evolution.ID
+	resp.Generation = evolution.Generation()
+	resp.Generations = evolution.Options.Generations
+	select {
+	case <-evolution.Done():
+		resp.Done = true
+	default:
+	}
+
+	resp.Best = make([]EvolvedSeed, 0)
+	for _, candidate := range evolution.Best() {
+		seed := EvolvedSeed{Genome: candidate.Genome, Seed: candidate.Seed, Fitness: candidate.Fitness, Generation: candidate.Generation}
+		if candidate.Biologist != nil {
+			seed.ID = candidate.Biologist.ID
+		}
+		resp.Best = append(resp.Best, seed)
+	}
+
+	return resp
+}
+
+// Limits on a single evolution so that a request cannot tie up the server indefinitely
+const (
+	maxEvolveSide           = 256
+	maxEvolvePopulation     = 256
+	maxEvolveGenerations    = 1000
+	maxEvolveMaxGenerations = 5000
+)
+
+// evolveSeeds starts an evolution when posted an EvolveRequest. The path /evolve/{id} reports
+// on an evolution or removes it when deleted and /evolve/{id}/stop stops it
+func evolveSeeds(mgr *biologist.Manager, checkpointDir string, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/evolve"), "/")
+
+	if name == "" && r.Method == http.MethodPost {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1048576))
+		if err != nil {
+			panic(err)
+		}
+		if err := r.Body.Close(); err != nil {
+			panic(err)
+		}
+
+		// Anything not specified falls back to the defaults
+		opts := biologist.DefaultEvolutionOptions()
+		rules := opts.Rules
+		req := EvolveRequest{
+			Dims:           opts.Dims,
+			Box:            opts.Box,
+			Rules:          rules.String(),
+			Objective:      opts.Objective.String(),
+			PopulationSize: opts.PopulationSize,
+			Generations:    opts.Generations,
+			MaxGenerations: opts.MaxGenerations,
+			MutationRate:   opts.MutationRate,
+			Seed:           opts.Seed,
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			log.Printf("ERROR: Could not handle request: %s\n", err)
+			postJSON(w, 422, err)
+			return
+		}
+
+		if opts.Rules, err = biologist.ParseRules(req.Rules); err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		if opts.Objective, err = biologist.ParseObjective(req.Objective); err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		if req.Dims.Width > maxEvolveSide || req.Dims.Height > maxEvolveSide {
+			http.Error(w, fmt.Sprintf("the board must be at most %dx%d", maxEvolveSide, maxEvolveSide), 422)
+			return
+		}
+		if req.PopulationSize > maxEvolvePopulation {
+			http.Error(w, fmt.Sprintf("the population must be at most %d", maxEvolvePopulation), 422)
+			return
+		}
+		if req.Generations > maxEvolveGenerations {
+			http.Error(w, fmt.Sprintf("the number of generations must be at most %d", maxEvolveGenerations), 422)
+			return
+		}
+		if req.MaxGenerations > maxEvolveMaxGenerations {
+			http.Error(w, fmt.Sprintf("the generations each seed is run for must be at most %d", maxEvolveMaxGenerations), 422)
+			return
+		}
+		opts.Dims, opts.Box, opts.Period = req.Dims, req.Box, req.Period
+		opts.PopulationSize, opts.Generations, opts.MaxGenerations = req.PopulationSize, req.Generations, req.MaxGenerations
+		opts.MutationRate, opts.Seed = req.MutationRate, req.Seed
+
+		evolution, err := biologist.NewEvolution(opts)
+		if err != nil {
+			http.Error(w, err.Error(), 422)
+			return
+		}
+		if checkpointDir != "" {
+			evolution.SetCheckpointDir(checkpointDir)
+		}
+		mgr.AddEvolution(evolution)
+		evolution.Start()
+
+		log.Printf("Evolving seeds as %x\n", evolution.ID)
+		postJSON(w, http.StatusCreated, newEvolveResponse(evolution))
+		return
+	}
+
+	stop := strings.HasSuffix(name, "/stop")
+	id, err := hex.DecodeString(strings.TrimSuffix(name, "/stop"))
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	evolution := mgr.Evolution(id)
+	if evolution == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method == http.MethodDelete {
+		mgr.RemoveEvolution(id)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if stop {
+		evolution.Stop()
+	}
+
+	postJSON(w, http.StatusOK, newEvolveResponse(evolution))
+}
+
+// resumeEvolutions picks up the evolutions which were checkpointed in the directory
+func resumeEvolutions(mgr *biologist.Manager, dir string, log *log.Logger) error {
+	checkpoints, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, checkpoint := range checkpoints {
+		file, err := os.Open(checkpoint)
+		if err != nil {
+			return err
+		}
+		evolution, err := biologist.ResumeEvolution(file)
+		file.Close()
+		if err != nil {
+			log.Printf("ERROR: Could not resume evolution from %s: %s\n", checkpoint, err)
+			continue
+		}
+
+		evolution.SetCheckpointDir(dir)
+		mgr.AddEvolution(evolution)
+		evolution.Start()
+	}
+
+	return nil
+}
+
+/////////////////////////////////// PREDECESSORS ///////////////////////////////////
+
+// Limits on a single search so that a request cannot tie up the server indefinitely
+const (
+	maxPredecessorGenerations = 10
+	maxPredecessorMargin      = 8
+	maxPredecessorNodes       = 100000000
+)
+
+// findPredecessors answers whether a generation could have arisen from an earlier configuration. The path is of
+// the form /predecessor/{id}/{gen}, where generation 0 is the submitted seed, and the query accepts the number
+// of generations to go back, the margin around the living cells to search within and the most cells to try
+func findPredecessors(mgr *biologist.Manager, log *log.Logger, w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/predecessor/"), "/"), "/")
+
+	id, err := hex.DecodeString(parts[0])
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+	generation := 0
+	if len(parts) > 1 {
+		if generation, err = strconv.Atoi(parts[1]); err != nil {
+			http.Error(w, "invalid generation", 422)
+			return
+		}
+	}
+
+	b := mgr.Biologist(id)
+	if b == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	opts := biologist.DefaultPredecessorOptions()
+	generations, err := queryInt(r, "generations", 1)
+	if err != nil || generations > maxPredecessorGenerations {
+		http.Error(w, fmt.Sprintf("the number of generations must be at most %d", maxPredecessorGenerations), 422)
+		return
+	}
+	if opts.Margin, err = queryInt(r, "margin", opts.Margin); err != nil || opts.Margin > maxPredecessorMargin {
+		http.Error(w, fmt.Sprintf("the margin must be at most %d", maxPredecessorMargin), 422)
+		return
+	}
+	if opts.MaxNodes, err = queryInt(r, "maxnodes", opts.MaxNodes); err != nil || opts.MaxNodes > maxPredecessorNodes {
+		http.Error(w, fmt.Sprintf("the maximum nodes must be at most %d", maxPredecessorNodes), 422)
+		return
+	}
+
+	predecessors, err := b.Predecessors(generation, generations, opts)
+	if err != nil {
+		http.Error(w, err.Error(), 422)
+		return
+	}
+
+	log.Printf("Predecessors of generation %d of %x: %s after %d nodes\n", generation, id, predecessors.Result.String(), predecessors.Nodes)
+	postJSON(w, http.StatusOK, struct {
+		Result      string
+		Generations [][]life.Location
+		Nodes       int
+	}{predecessors.Result.String(), predecessors.Generations, predecessors.Nodes})
+}
+
 /////////////////////////////////// OTHER ///////////////////////////////////
 
 func postJSON(w http.ResponseWriter, httpStatus int, send interface{}) {
@@ -309,12 +1300,68 @@ func postJSON(w http.ResponseWriter, httpStatus int, send interface{}) {
 func main() {
 	logger := log.New(os.Stdout, "[biologistd] ", 0)
 	portPtr := flag.Int("port", 8081, "Specify the port to use")
+	catalogPtr := flag.String("catalog", "", "Specify a SQLite database file in which to catalog finished runs")
+	trainPtr := flag.Duration("train-every", 0, "Specify how often to retrain the outcome predictor on the finished runs")
+	stateDirPtr := flag.String("state-dir", "", "Specify a directory in which to keep analyses across restarts")
 	flag.Parse()
 
 	mux := http.NewServeMux()
 
 	mgr := biologist.NewManager()
 
+	var catalog *biologist.Catalog
+	if *catalogPtr != "" {
+		db, err := sql.Open("sqlite3", *catalogPtr)
+		if err != nil {
+			logger.Fatalf("Could not open catalog: %s\n", err)
+		}
+		if catalog, err = biologist.NewCatalog(db); err != nil {
+			logger.Fatalf("Could not create catalog: %s\n", err)
+		}
+		mgr.SetCatalog(catalog)
+	}
+
+	var checkpointDir string
+	if *stateDirPtr != "" {
+		storage, err := biologist.NewFileStorage(*stateDirPtr)
+		if err != nil {
+			logger.Fatalf("Could not open state directory: %s\n", err)
+		}
+		mgr.SetStorage(storage)
+		if err := mgr.Restore(); err != nil {
+			logger.Fatalf("Could not restore analyses: %s\n", err)
+		}
+
+		checkpointDir = filepath.Join(*stateDirPtr, "evolutions")
+		if err := os.MkdirAll(checkpointDir, 0755); err != nil {
+			logger.Fatalf("Could not create evolution directory: %s\n", err)
+		}
+		if err := resumeEvolutions(mgr, checkpointDir, logger); err != nil {
+			logger.Fatalf("Could not resume evolutions: %s\n", err)
+		}
+
+		// Checkpoint before shutting down
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			if err := mgr.Snapshot(); err != nil {
+				logger.Printf("ERROR: Could not checkpoint analyses: %s\n", err)
+			}
+			os.Exit(0)
+		}()
+	}
+
+	if *trainPtr > 0 {
+		go func() {
+			for range time.Tick(*trainPtr) {
+				if err := mgr.TrainPredictor(1000, 0.5); err != nil {
+					logger.Printf("Could not train predictor: %s\n", err)
+				}
+			}
+		}()
+	}
+
 	mux.HandleFunc("/analyze",
 		func(w http.ResponseWriter, r *http.Request) {
 			createAnalysis(mgr, logger, w, r)
@@ -327,6 +1374,50 @@ func main() {
 		func(w http.ResponseWriter, r *http.Request) {
 			controlAnalysis(mgr, logger, w, r)
 		})
+	mux.HandleFunc("/export/",
+		func(w http.ResponseWriter, r *http.Request) {
+			exportAnalysis(mgr, logger, w, r)
+		})
+	mux.HandleFunc("/render/",
+		func(w http.ResponseWriter, r *http.Request) {
+			renderAnalysis(mgr, logger, w, r)
+		})
+	mux.HandleFunc("/snapshot/",
+		func(w http.ResponseWriter, r *http.Request) {
+			snapshotAnalysis(mgr, logger, w, r)
+		})
+	mux.HandleFunc("/heatmap/",
+		func(w http.ResponseWriter, r *http.Request) {
+			heatmapAnalysis(mgr, logger, w, r)
+		})
+	mux.HandleFunc("/dataset/",
+		func(w http.ResponseWriter, r *http.Request) {
+			exportDataset(mgr, catalog, logger, w, r)
+		})
+	mux.HandleFunc("/explore",
+		func(w http.ResponseWriter, r *http.Request) {
+			exploreRules(mgr, catalog, logger, w, r)
+		})
+	mux.HandleFunc("/explore/",
+		func(w http.ResponseWriter, r *http.Request) {
+			exploreRules(mgr, catalog, logger, w, r)
+		})
+	mux.HandleFunc("/evolve",
+		func(w http.ResponseWriter, r *http.Request) {
+			evolveSeeds(mgr, checkpointDir, logger, w, r)
+		})
+	mux.HandleFunc("/evolve/",
+		func(w http.ResponseWriter, r *http.Request) {
+			evolveSeeds(mgr, checkpointDir, logger, w, r)
+		})
+	mux.HandleFunc("/predecessor/",
+		func(w http.ResponseWriter, r *http.Request) {
+			findPredecessors(mgr, logger, w, r)
+		})
+	mux.HandleFunc("/search",
+		func(w http.ResponseWriter, r *http.Request) {
+			searchCatalog(catalog, logger, w, r)
+		})
 
 	http.ListenAndServe(fmt.Sprintf(":%d", *portPtr), mux)
 }
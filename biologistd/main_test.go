@@ -1,9 +1,17 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
 	"gitlab.com/hokiegeek/biologist"
 	"gitlab.com/hokiegeek/life"
-	"testing"
 )
 
 func TestNewCreateAnalysisResponse(t *testing.T) {
@@ -20,6 +28,66 @@ func TestNewCreateAnalysisResponse(t *testing.T) {
 	}
 }
 
+func TestExportAnalysis(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	b, err := biologist.New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	mgr := biologist.NewManager()
+	mgr.Add(b)
+
+	logger := log.New(ioutil.Discard, "", 0)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", fmt.Sprintf("/export/%x/0.rle", b.ID), nil)
+	exportAnalysis(mgr, logger, w, r)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status 200 but received %d\n", w.Code)
+	}
+	if !strings.HasPrefix(w.Body.String(), "x = ") || !strings.Contains(w.Body.String(), "rule = B3/S23") {
+		t.Fatalf("Received unexpected RLE: %s\n", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", fmt.Sprintf("/export/%x/0.xyz", b.ID), nil)
+	exportAnalysis(mgr, logger, w, r)
+	if w.Code != 422 {
+		t.Fatalf("Expected status 422 for an unknown format but received %d\n", w.Code)
+	}
+}
+
+func TestCreateAnalysisPattern(t *testing.T) {
+	mgr := biologist.NewManager()
+	logger := log.New(ioutil.Discard, "", 0)
+
+	// A glider centered on the origin, as found in pattern collections
+	req := `{"Dims": {"Width": 16, "Height": 16}, "Pattern": "#Life 1.06\n0 -1\n1 0\n-1 1\n0 1\n1 1\n"}`
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/analyze", strings.NewReader(req))
+	createAnalysis(mgr, logger, w, r)
+	if w.Code != 201 {
+		t.Fatalf("Expected status 201 but received %d: %s\n", w.Code, w.Body.String())
+	}
+
+	var resp CreateAnalysisResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Unable to read response: %s\n", err)
+	}
+	b := mgr.Biologist(resp.ID)
+	if b == nil {
+		t.Fatal("Analysis was not added to the manager")
+	}
+	if seed := b.Seed(); len(seed) != 5 {
+		t.Fatalf("Expected all 5 cells of the glider on the board but found %v\n", seed)
+	}
+	if census := b.Census(0); len(census.Objects) != 1 || census.Objects[0].Label != "glider" {
+		t.Errorf("Seed is not a glider: %s\n", census.String())
+	}
+}
+
 /*
 func TestNewBiologistUpdateResponse(t *testing.T) {
 	size := life.Dimensions{Width: 3, Height: 3}
@@ -33,3 +101,159 @@ func TestNewBiologistUpdateResponse(t *testing.T) {
 */
 
 // vim: set foldmethod=marker:
+
+func TestRenderAnalysisLimits(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	b, err := biologist.New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	mgr := biologist.NewManager()
+	mgr.Add(b)
+
+	logger := log.New(ioutil.Discard, "", 0)
+
+	for _, query := range []string{"count=0", "count=100000", "cellsize=100000", "delay=0", "delay=65536", "count=1000&cellsize=1000"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", fmt.Sprintf("/render/%x?%s", b.ID, query), nil)
+		renderAnalysis(mgr, logger, w, r)
+		if w.Code != 422 {
+			t.Errorf("Expected status 422 for %s but received %d\n", query, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", fmt.Sprintf("/snapshot/%x/0.png?cellsize=100000", b.ID), nil)
+	snapshotAnalysis(mgr, logger, w, r)
+	if w.Code != 422 {
+		t.Errorf("Expected status 422 for an oversized snapshot but received %d\n", w.Code)
+	}
+}
+
+func TestFindPredecessorsLimits(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	b, err := biologist.New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	mgr := biologist.NewManager()
+	mgr.Add(b)
+
+	logger := log.New(ioutil.Discard, "", 0)
+
+	for _, query := range []string{"margin=1000", "maxnodes=1000000000000", "generations=1000"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", fmt.Sprintf("/predecessor/%x/0?%s", b.ID, query), nil)
+		findPredecessors(mgr, logger, w, r)
+		if w.Code != 422 {
+			t.Errorf("Expected status 422 for %s but received %d\n", query, w.Code)
+		}
+	}
+}
+
+func TestCreateAnalysisLargeRLE(t *testing.T) {
+	mgr := biologist.NewManager()
+	logger := log.New(ioutil.Discard, "", 0)
+
+	for _, rle := range []string{`999999999o!`, `x = 64, y = 1\n64o!`} {
+		req := fmt.Sprintf(`{"Dims": {"Width": 16, "Height": 16}, "RLE": "%s"}`, rle)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/analyze", strings.NewReader(req))
+		createAnalysis(mgr, logger, w, r)
+		if w.Code != 422 {
+			t.Errorf("Expected status 422 for %s but received %d\n", rle, w.Code)
+		}
+	}
+}
+
+func TestExploreRulesLimits(t *testing.T) {
+	mgr := biologist.NewManager()
+	logger := log.New(ioutil.Discard, "", 0)
+
+	for _, req := range []string{
+		`{"Dims": {"Width": 100000, "Height": 32}}`,
+		`{"Soups": 1000000}`,
+		`{"MaxGenerations": 1000000000}`,
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/explore", strings.NewReader(req))
+		exploreRules(mgr, nil, logger, w, r)
+		if w.Code != 422 {
+			t.Errorf("Expected status 422 for %s but received %d\n", req, w.Code)
+		}
+	}
+}
+
+func TestEvolveSeedsLimits(t *testing.T) {
+	mgr := biologist.NewManager()
+	logger := log.New(ioutil.Discard, "", 0)
+
+	for _, req := range []string{
+		`{"Dims": {"Width": 32, "Height": 100000}}`,
+		`{"PopulationSize": 1000000}`,
+		`{"Generations": 1000000000}`,
+		`{"MaxGenerations": 1000000000}`,
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/evolve", strings.NewReader(req))
+		evolveSeeds(mgr, "", logger, w, r)
+		if w.Code != 422 {
+			t.Errorf("Expected status 422 for %s but received %d\n", req, w.Code)
+		}
+	}
+}
+
+func TestRemoveExplorer(t *testing.T) {
+	filter := biologist.RuleFilter{
+		Born:       []int{3},
+		NotBorn:    []int{0, 1, 2, 4, 5, 6, 7, 8},
+		Survive:    []int{2, 3},
+		NotSurvive: []int{0, 1, 4, 5, 6, 7, 8},
+	}
+	opts := biologist.DefaultExplorerOptions()
+	opts.Dims, opts.Soups, opts.MaxGenerations = life.Dimensions{Width: 8, Height: 8}, 1, 10
+	explorer, err := biologist.NewExplorer(filter, opts)
+	if err != nil {
+		t.Fatalf("Unable to create explorer: %s\n", err)
+	}
+
+	mgr := biologist.NewManager()
+	mgr.AddExplorer(explorer)
+	logger := log.New(ioutil.Discard, "", 0)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", fmt.Sprintf("/explore/%x", explorer.ID), nil)
+	exploreRules(mgr, nil, logger, w, r)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d but received %d\n", http.StatusNoContent, w.Code)
+	}
+	if mgr.Explorer(explorer.ID) != nil {
+		t.Error("Removed explorer is still tracked")
+	}
+}
+
+func TestRemoveEvolution(t *testing.T) {
+	opts := biologist.DefaultEvolutionOptions()
+	opts.Dims, opts.Box = life.Dimensions{Width: 8, Height: 8}, life.Dimensions{Width: 2, Height: 2}
+	opts.PopulationSize, opts.Generations, opts.MaxGenerations = 2, 1, 10
+	evolution, err := biologist.NewEvolution(opts)
+	if err != nil {
+		t.Fatalf("Unable to create evolution: %s\n", err)
+	}
+
+	mgr := biologist.NewManager()
+	mgr.AddEvolution(evolution)
+	logger := log.New(ioutil.Discard, "", 0)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", fmt.Sprintf("/evolve/%x", evolution.ID), nil)
+	evolveSeeds(mgr, "", logger, w, r)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d but received %d\n", http.StatusNoContent, w.Code)
+	}
+	if mgr.Evolution(evolution.ID) != nil {
+		t.Error("Removed evolution is still tracked")
+	}
+}
@@ -0,0 +1,295 @@
+package biologist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Simulation evolves the cells of a board, sending each generation to a listener once started
+type Simulation interface {
+	Dimensions() life.Dimensions
+	Start(listener chan *life.Generation) func()
+	String() string
+}
+
+// stateGeneration is a generation which also carries the cells passing through refractory states
+type stateGeneration struct {
+	life.Generation
+	Dying []CellState
+}
+
+// multiStateSimulation is a simulation which keeps track of dying cells under Generations rules
+type multiStateSimulation interface {
+	startStates(listener chan *stateGeneration) func()
+	resume(dying []CellState)
+}
+
+// board is a simulation of Life-like rules which supports the topologies and neighborhoods the life engine lacks
+type board struct { // {{{
+	dims     life.Dimensions
+	topology Topology
+	offsets  []life.Location
+	rules    func(int, bool) bool
+	states   int
+	mutex    sync.RWMutex
+	living   map[life.Location]bool
+	dying    map[life.Location]int
+	min      life.Location
+	max      life.Location
+	gen      int
+	Seed     []life.Location
+}
+
+// Dimensions returns the size of the board, which only frames the seed of an unbounded board
+func (t *board) Dimensions() life.Dimensions {
+	return t.dims
+}
+
+// bounds returns the corners of the box which contains the board along with every cell which has lived
+// beyond its frame, if it is unbounded
+func (t *board) bounds() (life.Location, life.Location) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.min, t.max
+}
+
+// grow extends the bounds to include the location
+func (t *board) grow(loc life.Location) {
+	if loc.X < t.min.X {
+		t.min.X = loc.X
+	}
+	if loc.Y < t.min.Y {
+		t.min.Y = loc.Y
+	}
+	if loc.X > t.max.X {
+		t.max.X = loc.X
+	}
+	if loc.Y > t.max.Y {
+		t.max.Y = loc.Y
+	}
+}
+
+// cells lists the living cells, row by row
+func (t *board) cells() []life.Location {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	cells := make([]life.Location, 0, len(t.living))
+	for loc := range t.living {
+		cells = append(cells, loc)
+	}
+	sortLocations(cells)
+	return cells
+}
+
+// dyingCells lists the cells in refractory states, row by row
+func (t *board) dyingCells() []CellState {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	cells := make([]CellState, 0, len(t.dying))
+	for loc, state := range t.dying {
+		cells = append(cells, CellState{Location: loc, State: state})
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y == cells[j].Y {
+			return cells[i].X < cells[j].X
+		}
+		return cells[i].Y < cells[j].Y
+	})
+	return cells
+}
+
+// resume puts the given cells back into their refractory states
+func (t *board) resume(dying []CellState) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, cell := range dying {
+		if cell.State > stateAlive && cell.State < t.states {
+			t.dying[cell.Location] = cell.State
+			t.grow(cell.Location)
+		}
+	}
+}
+
+// neighbors counts the living cells around the location
+func (t *board) neighbors(loc life.Location) int {
+	count := 0
+	for _, offset := range t.offsets {
+		if neighbor, onBoard := t.topology.wrap(t.dims, life.Location{X: loc.X + offset.X, Y: loc.Y + offset.Y}); onBoard && t.living[neighbor] {
+			count++
+		}
+	}
+	return count
+}
+
+// step evolves every cell of the board by a generation
+func (t *board) step() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	next := make(map[life.Location]bool, len(t.living))
+	nextDying := make(map[life.Location]int, len(t.dying))
+	evolve := func(loc life.Location) {
+		if state, dying := t.dying[loc]; dying {
+			// Dying cells can neither be born nor count as neighbors until they are dead
+			if state+1 < t.states {
+				nextDying[loc] = state + 1
+			}
+			return
+		}
+		if t.rules(t.neighbors(loc), t.living[loc]) {
+			next[loc] = true
+			t.grow(loc)
+		} else if t.living[loc] && t.states > 2 {
+			nextDying[loc] = stateAlive + 1
+		}
+	}
+
+	if t.topology == Unbounded {
+		// Only the cells around the living ones can change, wherever on the plane they are
+		visited := make(map[life.Location]bool)
+		around := append([]life.Location{{}}, t.offsets...)
+		for cell := range t.living {
+			for _, offset := range around {
+				if loc := (life.Location{X: cell.X + offset.X, Y: cell.Y + offset.Y}); !visited[loc] {
+					visited[loc] = true
+					evolve(loc)
+				}
+			}
+		}
+		for loc := range t.dying {
+			if !visited[loc] {
+				evolve(loc)
+			}
+		}
+	} else {
+		for y := 0; y < t.dims.Height; y++ {
+			for x := 0; x < t.dims.Width; x++ {
+				evolve(life.Location{X: x, Y: y})
+			}
+		}
+	}
+
+	t.living = next
+	t.dying = nextDying
+	t.gen++
+}
+
+// start evolves the board in the background, handing each new generation to send until stopped
+// or until send returns false
+func (t *board) start(send func(stop chan struct{}) bool) func() {
+	stop := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			t.step()
+			if !send(stop) {
+				return
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(stop) }) }
+}
+
+// Start evolves the board in the background, sending each new generation to the listener until stopped
+func (t *board) Start(listener chan *life.Generation) func() {
+	return t.start(func(stop chan struct{}) bool {
+		generation := &life.Generation{Num: t.gen, Living: t.cells()}
+		select {
+		case listener <- generation:
+			return true
+		case <-stop:
+			return false
+		}
+	})
+}
+
+// startStates evolves the board in the background, sending each new generation along with its dying cells
+// to the listener until stopped
+func (t *board) startStates(listener chan *stateGeneration) func() {
+	return t.start(func(stop chan struct{}) bool {
+		generation := &stateGeneration{Generation: life.Generation{Num: t.gen, Living: t.cells()}, Dying: t.dyingCells()}
+		select {
+		case listener <- generation:
+			return true
+		case <-stop:
+			return false
+		}
+	})
+}
+
+func (t *board) String() string {
+	var buf bytes.Buffer
+	living := t.cells()
+	alive := make(map[life.Location]bool, len(living))
+	for _, loc := range living {
+		alive[loc] = true
+	}
+	dying := make(map[life.Location]bool)
+	for _, cell := range t.dyingCells() {
+		dying[cell.Location] = true
+	}
+
+	min, max := t.bounds()
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			if loc := (life.Location{X: x, Y: y}); alive[loc] {
+				buf.WriteString("O")
+			} else if dying[loc] {
+				buf.WriteString("o")
+			} else {
+				buf.WriteString(".")
+			}
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func newBoard(dims life.Dimensions, seed func(life.Dimensions, life.Location) []life.Location, rules Rules, topology Topology) (*board, error) {
+	if dims.Width <= 0 || dims.Height <= 0 {
+		return nil, errors.New("invalid dimensions")
+	}
+
+	if tester := rules.Tester(); topology == Unbounded && tester(0, false) {
+		return nil, fmt.Errorf("cells born without neighbors under %s would fill an unbounded board", rules.String())
+	}
+
+	b := new(board)
+	b.dims = dims
+	b.max = life.Location{X: dims.Width - 1, Y: dims.Height - 1}
+	b.topology = topology
+	b.offsets = rules.offsets()
+	b.rules = rules.Tester()
+	b.states = rules.states()
+
+	b.living = make(map[life.Location]bool)
+	b.dying = make(map[life.Location]int)
+	for _, loc := range seed(dims, life.Location{X: 0, Y: 0}) {
+		if wrapped, onBoard := topology.wrap(dims, loc); onBoard {
+			b.living[wrapped] = true
+			b.grow(wrapped)
+		}
+	}
+	b.Seed = b.cells()
+
+	return b, nil
+} // }}}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,137 @@
+package biologist
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strconv"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// knownObjects maps the canonical hash of common objects to their names
+var knownObjects = make(map[string]string)
+
+func init() {
+	objects := map[string][][]string{
+		"block":   {{"OO", "OO"}},
+		"beehive": {{".OO.", "O..O", ".OO."}},
+		"loaf":    {{".OO.", "O..O", ".O.O", "..O."}},
+		"boat":    {{"OO.", "O.O", ".O."}},
+		"ship":    {{"OO.", "O.O", ".OO"}},
+		"tub":     {{".O.", "O.O", ".O."}},
+		"pond":    {{".OO.", "O..O", "O..O", ".OO."}},
+		"blinker": {{"OOO"}},
+		"glider":  {{".O.", "..O", "OOO"}, {"O.O", ".OO", ".O."}},
+	}
+	for name, phases := range objects {
+		for _, rows := range phases {
+			knownObjects[CanonicalHash(cellsFromRows(rows))] = name
+		}
+	}
+}
+
+// cellsFromRows creates the living cells described by rows of text where 'O' is alive
+func cellsFromRows(rows []string) []life.Location {
+	cells := make([]life.Location, 0)
+	for y, row := range rows {
+		for x, c := range row {
+			if c == 'O' {
+				cells = append(cells, life.Location{X: x, Y: y})
+			}
+		}
+	}
+	return cells
+}
+
+func sortLocations(cells []life.Location) {
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y == cells[j].Y {
+			return cells[i].X < cells[j].X
+		}
+		return cells[i].Y < cells[j].Y
+	})
+}
+
+// lessLocations compares two sorted lists of cells of equal length
+func lessLocations(lhs, rhs []life.Location) bool {
+	for i := range lhs {
+		if lhs[i].Y != rhs[i].Y {
+			return lhs[i].Y < rhs[i].Y
+		}
+		if lhs[i].X != rhs[i].X {
+			return lhs[i].X < rhs[i].X
+		}
+	}
+	return false
+}
+
+// Canonical reduces the given cells to the smallest of all of their rotations and reflections, translated to the origin
+func Canonical(cells []life.Location) []life.Location {
+	return canonicalOver(cells, allTransforms)
+}
+
+// canonicalOver reduces the given cells to the smallest of the given transforms of them, translated to the origin
+func canonicalOver(cells []life.Location, transformSet []int) []life.Location {
+	var canonical []life.Location
+	for _, transform := range transformSet {
+		candidate := transformCells(cells, transform)
+		sortLocations(candidate)
+		if canonical == nil || lessLocations(candidate, canonical) {
+			canonical = candidate
+		}
+	}
+	return canonical
+}
+
+// CanonicalHash returns a hash which is identical for any cells which share a canonical form
+func CanonicalHash(cells []life.Location) string {
+	return hashCells(Canonical(cells))
+}
+
+// equivalenceHash returns a hash which is identical for any seeds which evolve alike on boards of the given
+// size and topology. Seeds are only moved to the origin where the topology has no walls or twists to evolve
+// differently next to, otherwise they are only rotated and reflected along with the board
+func equivalenceHash(cells []life.Location, dims life.Dimensions, topology Topology) string {
+	if topology.translatable() {
+		return hashCells(canonicalOver(cells, topology.symmetries(dims)))
+	}
+	return hashCells(canonicalOnBoard(cells, dims, topology.symmetries(dims)))
+}
+
+// canonicalOnBoard reduces the given cells to the smallest of the given transforms of the board they are on,
+// keeping their positions relative to the corner of the board
+func canonicalOnBoard(cells []life.Location, dims life.Dimensions, transformSet []int) []life.Location {
+	var canonical []life.Location
+	for _, transform := range transformSet {
+		corner, _ := bounds([]life.Location{
+			transforms[transform](life.Location{X: 0, Y: 0}),
+			transforms[transform](life.Location{X: dims.Width - 1, Y: dims.Height - 1}),
+		})
+		candidate := make([]life.Location, len(cells))
+		for i, loc := range cells {
+			moved := transforms[transform](loc)
+			candidate[i] = life.Location{X: moved.X - corner.X, Y: moved.Y - corner.Y}
+		}
+		sortLocations(candidate)
+		if canonical == nil || lessLocations(candidate, canonical) {
+			canonical = candidate
+		}
+	}
+	return canonical
+}
+
+func hashCells(cells []life.Location) string {
+	var str bytes.Buffer
+	for _, loc := range cells {
+		str.WriteString(strconv.Itoa(loc.X))
+		str.WriteString(",")
+		str.WriteString(strconv.Itoa(loc.Y))
+		str.WriteString(";")
+	}
+
+	h := sha1.New()
+	h.Write(str.Bytes())
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestCanonical(t *testing.T) {
+	rPentomino := []life.Location{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}}
+	expected := Canonical(rPentomino)
+
+	for transform := range transforms {
+		moved := transformCells(rPentomino, transform)
+		for i := range moved {
+			moved[i].X += 7
+			moved[i].Y -= 3
+		}
+
+		canonical := Canonical(moved)
+		if len(canonical) != len(expected) {
+			t.Fatalf("Canonical form of transform %d has %d cells instead of %d\n", transform, len(canonical), len(expected))
+		}
+		for i := range canonical {
+			if !canonical[i].Equals(&expected[i]) {
+				t.Fatalf("Canonical form of transform %d differs at cell %d: %s vs %s\n", transform, i, canonical[i].String(), expected[i].String())
+			}
+		}
+
+		if CanonicalHash(moved) != CanonicalHash(rPentomino) {
+			t.Errorf("Hash of transform %d differs from the original\n", transform)
+		}
+	}
+
+	min, _ := bounds(expected)
+	if min.X != 0 || min.Y != 0 {
+		t.Errorf("Canonical form was not translated to the origin: %s\n", min.String())
+	}
+}
+
+func TestCanonicalHashDistinct(t *testing.T) {
+	block := cellsFromRows([]string{"OO", "OO"})
+	beehive := cellsFromRows([]string{".OO.", "O..O", ".OO."})
+
+	if CanonicalHash(block) == CanonicalHash(beehive) {
+		t.Fatal("Block and beehive share a hash")
+	}
+}
+
+func TestKnownObjects(t *testing.T) {
+	census := takeCensus(cellsFromRows([]string{
+		"OO.....O.",
+		"OO......O",
+		"......OOO",
+	}))
+
+	labels := make(map[string]bool)
+	for _, object := range census.Objects {
+		labels[object.Label] = true
+	}
+
+	if !labels["block"] || !labels["glider"] {
+		t.Fatalf("Did not label the block and glider: %s\n", census.String())
+	}
+}
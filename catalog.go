@@ -0,0 +1,333 @@
+package biologist
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const catalogSchema = `CREATE TABLE IF NOT EXISTS runs (
+	id TEXT PRIMARY KEY,
+	rules TEXT NOT NULL,
+	width INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	seed_hash TEXT NOT NULL,
+	initial_population INTEGER NOT NULL,
+	density REAL NOT NULL,
+	status TEXT NOT NULL,
+	generations INTEGER NOT NULL,
+	cycle_start INTEGER NOT NULL,
+	cycle_length INTEGER NOT NULL,
+	population INTEGER NOT NULL,
+	objects INTEGER NOT NULL,
+	census TEXT NOT NULL,
+	wolfram TEXT NOT NULL DEFAULT ''
+)`
+
+// Catalogs created before a column was added to the runs table need it added
+var catalogMigrations = []struct {
+	column     string
+	definition string
+}{
+	{"wolfram", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// tableColumns lists the names of the columns of the table
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, primaryKey int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+const catalogRulesSchema = `CREATE TABLE IF NOT EXISTS rules (
+	exploration TEXT NOT NULL,
+	rules TEXT NOT NULL,
+	behavior TEXT NOT NULL,
+	dies INTEGER NOT NULL,
+	stable INTEGER NOT NULL,
+	chaotic INTEGER NOT NULL,
+	explosive INTEGER NOT NULL,
+	mean_generations REAL NOT NULL,
+	mean_density REAL NOT NULL,
+	PRIMARY KEY (exploration, rules)
+)`
+
+const catalogColumns = "id, rules, width, height, seed_hash, initial_population, density, status, generations, cycle_start, cycle_length, population, objects, census, wolfram"
+
+// CatalogEntry summarizes a finished run
+type CatalogEntry struct { // {{{
+	ID                string
+	Rules             string
+	Width             int
+	Height            int
+	SeedHash          string
+	InitialPopulation int
+	Density           float64
+	Status            string
+	Generations       int
+	CycleStart        int
+	CycleLength       int
+	Population        int
+	Objects           int
+	Census            map[string]int
+	Wolfram           string
+}
+
+func (t *CatalogEntry) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("%s %s %dx%d %s (%s)", t.ID, t.Rules, t.Width, t.Height, t.Status, t.Wolfram))
+	buf.WriteString(fmt.Sprintf(" after %d generations", t.Generations))
+	if t.CycleLength > 0 {
+		buf.WriteString(fmt.Sprintf(" (cycle of %d from %d)", t.CycleLength, t.CycleStart))
+	}
+	return buf.String()
+}
+
+func newCatalogEntry(biologist *Biologist) CatalogEntry {
+	var entry CatalogEntry
+
+	dims := biologist.Life.Dimensions()
+	entry.ID = fmt.Sprintf("%x", biologist.ID)
+	rules := biologist.Rules()
+	entry.Rules = rules.String()
+	entry.Width = dims.Width
+	entry.Height = dims.Height
+	entry.SeedHash = biologist.SeedHash()
+	entry.InitialPopulation = len(biologist.seed)
+	entry.Density = density(entry.InitialPopulation, dims.Width*dims.Height)
+
+	last := biologist.analyses.Count() - 1
+	final := biologist.analyses.Get(last)
+	entry.Status = final.Status.String()
+	entry.Generations = last
+	if detected, start, length := biologist.cycle(); detected {
+		entry.Status = Stable.String()
+		entry.Generations = start
+		entry.CycleStart = start
+		entry.CycleLength = length
+	}
+
+	entry.Wolfram = biologist.WolframClass().String()
+
+	census := biologist.census(final.Living)
+	entry.Population = census.Population
+	entry.Objects = len(census.Objects)
+	entry.Census = make(map[string]int)
+	for _, object := range census.Objects {
+		label := object.Label
+		if label == "" {
+			label = "unknown"
+		}
+		entry.Census[label]++
+	}
+
+	return entry
+} // }}}
+
+// CatalogQuery filters the runs in the catalog. Fields left at their zero value do not filter
+type CatalogQuery struct {
+	Rules          string
+	Width          int
+	Height         int
+	Status         string
+	MinDensity     float64
+	MaxDensity     float64
+	MinGenerations int
+	MaxGenerations int
+	SeedHash       string
+	Wolfram        string
+	Limit          int
+}
+
+// Catalog records a summary of each finished run to a SQL database so that they can be searched
+type Catalog struct { // {{{
+	db *sql.DB
+}
+
+// Record adds the summary of the given biologist's run, replacing any previous summary of it
+func (t *Catalog) Record(biologist *Biologist) error {
+	entry := newCatalogEntry(biologist)
+
+	census, err := json.Marshal(entry.Census)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.db.Exec("INSERT OR REPLACE INTO runs ("+catalogColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.ID, entry.Rules, entry.Width, entry.Height, entry.SeedHash, entry.InitialPopulation, entry.Density,
+		entry.Status, entry.Generations, entry.CycleStart, entry.CycleLength, entry.Population, entry.Objects, string(census), entry.Wolfram)
+	return err
+}
+
+// Remove deletes the summary of the run with the given ID
+func (t *Catalog) Remove(id []byte) error {
+	_, err := t.db.Exec("DELETE FROM runs WHERE id = ?", fmt.Sprintf("%x", id))
+	return err
+}
+
+// Search returns the summaries of the runs which match the query, longest lived first
+func (t *Catalog) Search(query CatalogQuery) ([]CatalogEntry, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	filter := func(condition string, arg interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+
+	if query.Rules != "" {
+		filter("rules = ?", query.Rules)
+	}
+	if query.Width > 0 {
+		filter("width = ?", query.Width)
+	}
+	if query.Height > 0 {
+		filter("height = ?", query.Height)
+	}
+	if query.Status != "" {
+		filter("status = ?", query.Status)
+	}
+	if query.MinDensity > 0 {
+		filter("density >= ?", query.MinDensity)
+	}
+	if query.MaxDensity > 0 {
+		filter("density <= ?", query.MaxDensity)
+	}
+	if query.MinGenerations > 0 {
+		filter("generations >= ?", query.MinGenerations)
+	}
+	if query.MaxGenerations > 0 {
+		filter("generations <= ?", query.MaxGenerations)
+	}
+	if query.SeedHash != "" {
+		filter("seed_hash = ?", query.SeedHash)
+	}
+	if query.Wolfram != "" {
+		filter("wolfram = ?", query.Wolfram)
+	}
+
+	statement := "SELECT " + catalogColumns + " FROM runs"
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	statement += " ORDER BY generations DESC, id"
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	rows, err := t.db.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]CatalogEntry, 0)
+	for rows.Next() {
+		var entry CatalogEntry
+		var census string
+		if err := rows.Scan(&entry.ID, &entry.Rules, &entry.Width, &entry.Height, &entry.SeedHash, &entry.InitialPopulation,
+			&entry.Density, &entry.Status, &entry.Generations, &entry.CycleStart, &entry.CycleLength,
+			&entry.Population, &entry.Objects, &census, &entry.Wolfram); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(census), &entry.Census); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// RecordRule adds the result of exploring a rule as part of the given exploration
+func (t *Catalog) RecordRule(exploration []byte, result RuleResult) error {
+	_, err := t.db.Exec("INSERT OR REPLACE INTO rules (exploration, rules, behavior, dies, stable, chaotic, explosive, mean_generations, mean_density) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		fmt.Sprintf("%x", exploration), result.Rules, result.Behavior,
+		result.Soups[RuleDies.String()], result.Soups[RuleStable.String()], result.Soups[RuleChaotic.String()], result.Soups[RuleExplosive.String()],
+		result.MeanGenerations, result.MeanDensity)
+	return err
+}
+
+// SearchRules returns the results of the explored rules with the given behavior, or of every rule if it is empty
+func (t *Catalog) SearchRules(behavior string, limit int) ([]RuleResult, error) {
+	statement := "SELECT rules, behavior, dies, stable, chaotic, explosive, mean_generations, mean_density FROM rules"
+	args := make([]interface{}, 0)
+	if behavior != "" {
+		statement += " WHERE behavior = ?"
+		args = append(args, behavior)
+	}
+	statement += " ORDER BY mean_generations DESC, rules"
+	if limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := t.db.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]RuleResult, 0)
+	for rows.Next() {
+		var result RuleResult
+		var dies, stable, chaotic, explosive int
+		if err := rows.Scan(&result.Rules, &result.Behavior, &dies, &stable, &chaotic, &explosive, &result.MeanGenerations, &result.MeanDensity); err != nil {
+			return nil, err
+		}
+		result.Soups = map[string]int{
+			RuleDies.String():      dies,
+			RuleStable.String():    stable,
+			RuleChaotic.String():   chaotic,
+			RuleExplosive.String(): explosive,
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// NewCatalog creates a catalog in the given database. The database driver is left up to the caller
+func NewCatalog(db *sql.DB) (*Catalog, error) {
+	for _, schema := range []string{catalogSchema, catalogRulesSchema} {
+		if _, err := db.Exec(schema); err != nil {
+			return nil, err
+		}
+	}
+
+	columns, err := tableColumns(db, "runs")
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range catalogMigrations {
+		if columns[migration.column] {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE runs ADD COLUMN %s %s", migration.column, migration.definition)); err != nil {
+			return nil, err
+		}
+	}
+
+	c := new(Catalog)
+	c.db = db
+
+	return c, nil
+} // }}}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,184 @@
+package biologist
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"gitlab.com/hokiegeek/life"
+)
+
+func tempCatalog(t *testing.T) (*Catalog, string) {
+	dir, err := ioutil.TempDir("", "catalog")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s\n", err)
+	}
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "catalog.db"))
+	if err != nil {
+		t.Fatalf("Unable to open database: %s\n", err)
+	}
+
+	catalog, err := NewCatalog(db)
+	if err != nil {
+		t.Fatalf("Unable to create catalog: %s\n", err)
+	}
+
+	return catalog, dir
+}
+
+func TestCatalogSearch(t *testing.T) {
+	catalog, dir := tempCatalog(t)
+	defer os.RemoveAll(dir)
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	blinker, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	blinker.Start()
+	<-blinker.Done()
+
+	lonely, err := New(size, Pattern([]life.Location{{X: 1, Y: 1}}), life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	lonely.Start()
+	<-lonely.Done()
+
+	for _, biologist := range []*Biologist{blinker, lonely, blinker} {
+		if err := catalog.Record(biologist); err != nil {
+			t.Fatalf("Unable to record biologist: %s\n", err)
+		}
+	}
+
+	all, err := catalog.Search(CatalogQuery{})
+	if err != nil {
+		t.Fatalf("Unable to search catalog: %s\n", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 runs in the catalog but found %d\n", len(all))
+	}
+
+	stable, err := catalog.Search(CatalogQuery{Rules: "B3/S23", Width: 3, Status: "Stable", MinDensity: 0.3})
+	if err != nil {
+		t.Fatalf("Unable to search catalog: %s\n", err)
+	}
+	if len(stable) != 1 {
+		t.Fatalf("Expected to find only the blinker but found %v\n", stable)
+	}
+	if stable[0].CycleLength != 2 || stable[0].InitialPopulation != 3 || stable[0].Population != 3 || stable[0].Census["blinker"] != 1 || stable[0].Wolfram != "Periodic" {
+		t.Errorf("Unexpected summary of blinker: %v\n", stable[0])
+	}
+
+	dead, err := catalog.Search(CatalogQuery{Status: "Dead", MaxDensity: 0.2, Wolfram: "Homogeneous"})
+	if err != nil {
+		t.Fatalf("Unable to search catalog: %s\n", err)
+	}
+	if len(dead) != 1 || dead[0].Generations != 1 {
+		t.Fatalf("Expected to find the lone cell dying after 1 generation but found %v\n", dead)
+	}
+
+	if err := catalog.Remove(lonely.ID); err != nil {
+		t.Fatalf("Unable to remove from catalog: %s\n", err)
+	}
+	if all, _ = catalog.Search(CatalogQuery{}); len(all) != 1 {
+		t.Errorf("Expected 1 run after removal but found %d\n", len(all))
+	}
+}
+
+func TestManagerCatalog(t *testing.T) {
+	catalog, dir := tempCatalog(t)
+	defer os.RemoveAll(dir)
+
+	mgr := NewManager()
+	mgr.SetCatalog(catalog)
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr.Add(biologist)
+	biologist.Start()
+
+	for tries := 0; tries < 100; tries++ {
+		if found, _ := catalog.Search(CatalogQuery{}); len(found) == 1 {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatal("Finished run was not cataloged")
+}
+
+func TestCatalogRemoved(t *testing.T) {
+	catalog, dir := tempCatalog(t)
+	defer os.RemoveAll(dir)
+
+	mgr := NewManager()
+	mgr.SetCatalog(catalog)
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr.Add(biologist)
+	mgr.Remove(biologist.ID)
+
+	biologist.Start()
+	<-biologist.Done()
+	time.Sleep(time.Millisecond * 10)
+
+	all, err := catalog.Search(CatalogQuery{})
+	if err != nil {
+		t.Fatalf("Unable to search catalog: %s\n", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("Expected the removed run to be left out of the catalog but found %d runs\n", len(all))
+	}
+}
+
+func TestCatalogMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalog")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "catalog.db"))
+	if err != nil {
+		t.Fatalf("Unable to open database: %s\n", err)
+	}
+	defer db.Close()
+
+	// The runs table as it was before the Wolfram class was cataloged
+	old := strings.Replace(catalogSchema, ",\n\twolfram TEXT NOT NULL DEFAULT ''", "", 1)
+	if _, err := db.Exec(old); err != nil {
+		t.Fatalf("Unable to create the old runs table: %s\n", err)
+	}
+
+	// Opening the catalog again finds the column already added
+	for i := 0; i < 2; i++ {
+		if _, err := NewCatalog(db); err != nil {
+			t.Fatalf("Unable to migrate catalog: %s\n", err)
+		}
+	}
+	columns, err := tableColumns(db, "runs")
+	if err != nil {
+		t.Fatalf("Unable to list the columns of the runs table: %s\n", err)
+	}
+	if !columns["wolfram"] {
+		t.Error("Catalog was not migrated")
+	}
+
+	db.Close()
+	if _, err := NewCatalog(db); err == nil {
+		t.Error("Created a catalog in a closed database")
+	}
+}
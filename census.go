@@ -0,0 +1,140 @@
+package biologist
+
+import (
+	"bytes"
+	"fmt"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Object is a group of living cells which touch each other
+type Object struct { // {{{
+	Cells []life.Location
+	Min   life.Location
+	Max   life.Location
+	Label string
+}
+
+func (t *Object) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	if t.Label != "" {
+		buf.WriteString(t.Label)
+		buf.WriteString(", ")
+	}
+	buf.WriteString(fmt.Sprintf("%d cells, ", len(t.Cells)))
+	buf.WriteString(t.Min.String())
+	buf.WriteString(" - ")
+	buf.WriteString(t.Max.String())
+	buf.WriteString("}")
+	return buf.String()
+} // }}}
+
+// Census provides a count of the living cells and the objects they make up
+type Census struct { // {{{
+	Population int
+	Objects    []Object
+}
+
+func (t *Census) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Population: %d\n", t.Population))
+	buf.WriteString(fmt.Sprintf("Objects: %d\n", len(t.Objects)))
+	for _, object := range t.Objects {
+		buf.WriteString("\t")
+		buf.WriteString(object.String())
+		buf.WriteString("\n")
+	}
+	return buf.String()
+} // }}}
+
+// Census counts the living cells of the analysis and the objects they make up
+func (t *Analysis) Census() Census {
+	return takeCensus(t.Living)
+}
+
+func takeCensus(living []life.Location) Census {
+	return takeCensusOn(living, planeGeometry)
+}
+
+// takeCensusOn finds the objects among the living cells, joining the cells within each other's neighborhood
+// and following them across the edges of the board as the geometry dictates. Objects are labeled by their shape as though they had not been wrapped
+func takeCensusOn(living []life.Location, geo geometry) Census {
+	var census Census
+	census.Population = len(living)
+	census.Objects = make([]Object, 0)
+
+	unvisited := make(map[life.Location]bool, len(living))
+	for _, loc := range living {
+		unvisited[loc] = true
+	}
+
+	// Flood each cell not yet claimed by an object to find all of its neighbors
+	for _, start := range living {
+		if !unvisited[start] {
+			continue
+		}
+		delete(unvisited, start)
+
+		object := Object{Min: start, Max: start}
+		shape := make([]life.Location, 0)
+		unwrapped := map[life.Location]life.Location{start: start}
+		queue := []life.Location{start}
+		for len(queue) > 0 {
+			loc := queue[0]
+			queue = queue[1:]
+
+			object.Cells = append(object.Cells, loc)
+			shape = append(shape, unwrapped[loc])
+			if loc.X < object.Min.X {
+				object.Min.X = loc.X
+			}
+			if loc.Y < object.Min.Y {
+				object.Min.Y = loc.Y
+			}
+			if loc.X > object.Max.X {
+				object.Max.X = loc.X
+			}
+			if loc.Y > object.Max.Y {
+				object.Max.Y = loc.Y
+			}
+
+			for _, offset := range geo.offsets {
+				neighbor, onBoard := geo.move(loc, offset.X, offset.Y)
+				if onBoard && unvisited[neighbor] {
+					delete(unvisited, neighbor)
+					unwrapped[neighbor] = life.Location{X: unwrapped[loc].X + offset.X, Y: unwrapped[loc].Y + offset.Y}
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		object.Label = knownObjects[CanonicalHash(shape)]
+
+		census.Objects = append(census.Objects, object)
+	}
+
+	return census
+}
+
+// bounds returns the top-left and bottom-right corners of the box which contains all of the given cells
+func bounds(cells []life.Location) (life.Location, life.Location) {
+	var min, max life.Location
+	for i, loc := range cells {
+		if i == 0 || loc.X < min.X {
+			min.X = loc.X
+		}
+		if i == 0 || loc.Y < min.Y {
+			min.Y = loc.Y
+		}
+		if i == 0 || loc.X > max.X {
+			max.X = loc.X
+		}
+		if i == 0 || loc.Y > max.Y {
+			max.Y = loc.Y
+		}
+	}
+	return min, max
+}
+
+// vim: set foldmethod=marker:
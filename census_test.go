@@ -0,0 +1,45 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestTakeCensus(t *testing.T) {
+	living := []life.Location{
+		// Block
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1},
+		// Blinker
+		{X: 5, Y: 0}, {X: 5, Y: 1}, {X: 5, Y: 2},
+		// Diagonal pair
+		{X: 9, Y: 9}, {X: 10, Y: 10},
+	}
+
+	census := takeCensus(living)
+
+	if census.Population != len(living) {
+		t.Fatalf("Expected population of %d but found %d\n", len(living), census.Population)
+	}
+
+	if len(census.Objects) != 3 {
+		t.Fatalf("Expected 3 objects but found %d\n", len(census.Objects))
+	}
+
+	blinker := census.Objects[1]
+	if len(blinker.Cells) != 3 {
+		t.Errorf("Expected blinker with 3 cells but found %d\n", len(blinker.Cells))
+	}
+	expectedMin := life.Location{X: 5, Y: 0}
+	expectedMax := life.Location{X: 5, Y: 2}
+	if !blinker.Min.Equals(&expectedMin) || !blinker.Max.Equals(&expectedMax) {
+		t.Errorf("Blinker has unexpected bounds %s - %s\n", blinker.Min.String(), blinker.Max.String())
+	}
+}
+
+func TestTakeCensusEmpty(t *testing.T) {
+	census := takeCensus([]life.Location{})
+	if census.Population != 0 || len(census.Objects) != 0 {
+		t.Fatalf("Expected empty census but found %d cells in %d objects\n", census.Population, len(census.Objects))
+	}
+}
@@ -0,0 +1,174 @@
+package biologist
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"math"
+	"sync"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+const (
+	// Size of the side of the blocks the board is tiled with when measuring the block entropy
+	complexityBlockSize = 2
+	// Size of the side of the regions the board is divided into when measuring the variance of the density
+	complexityRegionSize = 8
+)
+
+// Complexity quantifies how structured the living cells of a generation are beyond their population
+type Complexity struct { // {{{
+	// BlockEntropy is the Shannon entropy, in bits, of the patterns of the 2x2 blocks tiling the board
+	BlockEntropy float64
+	// Compressibility is the compressed size of the board's bitmap relative to its uncompressed size
+	Compressibility float64
+	// DensityVariance is the variance of the density of living cells across 8x8 regions of the board
+	DensityVariance float64
+}
+
+func (t *Complexity) String() string {
+	return fmt.Sprintf("block entropy %.3f, compressibility %.3f, density variance %.4f", t.BlockEntropy, t.Compressibility, t.DensityVariance)
+}
+
+// complexityOf measures the complexity of the living cells on a board of the given dimensions
+func complexityOf(origin life.Location, dims life.Dimensions, living []life.Location) Complexity {
+	var complexity Complexity
+	if dims.Width <= 0 || dims.Height <= 0 {
+		return complexity
+	}
+
+	buffers := complexityBuffersPool.Get().(*complexityBuffers)
+	defer complexityBuffersPool.Put(buffers)
+
+	alive := buffers.cells(dims.Width * dims.Height)
+	for _, loc := range living {
+		x, y := loc.X-origin.X, loc.Y-origin.Y
+		if x >= 0 && y >= 0 && x < dims.Width && y < dims.Height {
+			alive[y*dims.Width+x] = true
+		}
+	}
+	isAlive := func(x, y int) bool {
+		return x < dims.Width && y < dims.Height && alive[y*dims.Width+x]
+	}
+
+	complexity.BlockEntropy = blockEntropy(dims, isAlive)
+	complexity.Compressibility = buffers.compressibility(alive)
+	complexity.DensityVariance = densityVariance(dims, isAlive)
+
+	return complexity
+} // }}}
+
+func blockEntropy(dims life.Dimensions, isAlive func(int, int) bool) float64 {
+	counts := make(map[int]int)
+	blocks := 0
+	for y := 0; y < dims.Height; y += complexityBlockSize {
+		for x := 0; x < dims.Width; x += complexityBlockSize {
+			pattern := 0
+			for dy := 0; dy < complexityBlockSize; dy++ {
+				for dx := 0; dx < complexityBlockSize; dx++ {
+					pattern <<= 1
+					if isAlive(x+dx, y+dy) {
+						pattern |= 1
+					}
+				}
+			}
+			counts[pattern]++
+			blocks++
+		}
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(blocks)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// complexityBuffers are reused from one generation to the next instead of allocating the board's bitmaps
+// and a compressor for every generation
+type complexityBuffers struct {
+	alive  []bool
+	bitmap []byte
+	buf    bytes.Buffer
+	writer *flate.Writer
+}
+
+// complexityBuffersPool is shared by every biologist, as each generation is analyzed as soon as it evolves
+var complexityBuffersPool = sync.Pool{
+	New: func() interface{} {
+		b := new(complexityBuffers)
+		// The fastest level still tells structure apart from noise, at a fraction of the cost of the best compression
+		b.writer, _ = flate.NewWriter(&b.buf, flate.BestSpeed)
+		return b
+	},
+}
+
+// cells returns a cleared bitmap with a cell for each location of the board
+func (t *complexityBuffers) cells(size int) []bool {
+	if cap(t.alive) < size {
+		t.alive = make([]bool, size)
+	}
+	alive := t.alive[:size]
+	for i := range alive {
+		alive[i] = false
+	}
+	return alive
+}
+
+func (t *complexityBuffers) compressibility(alive []bool) float64 {
+	size := (len(alive) + 7) / 8
+	if cap(t.bitmap) < size {
+		t.bitmap = make([]byte, size)
+	}
+	bitmap := t.bitmap[:size]
+	for i := range bitmap {
+		bitmap[i] = 0
+	}
+	for i, cell := range alive {
+		if cell {
+			bitmap[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+
+	t.buf.Reset()
+	t.writer.Reset(&t.buf)
+	t.writer.Write(bitmap)
+	t.writer.Close()
+
+	return float64(t.buf.Len()) / float64(len(bitmap))
+}
+
+func densityVariance(dims life.Dimensions, isAlive func(int, int) bool) float64 {
+	densities := make([]float64, 0)
+	for y := 0; y < dims.Height; y += complexityRegionSize {
+		for x := 0; x < dims.Width; x += complexityRegionSize {
+			population := 0
+			area := 0
+			for dy := 0; dy < complexityRegionSize && y+dy < dims.Height; dy++ {
+				for dx := 0; dx < complexityRegionSize && x+dx < dims.Width; dx++ {
+					area++
+					if isAlive(x+dx, y+dy) {
+						population++
+					}
+				}
+			}
+			densities = append(densities, density(population, area))
+		}
+	}
+
+	mean := 0.0
+	for _, d := range densities {
+		mean += d
+	}
+	mean /= float64(len(densities))
+
+	variance := 0.0
+	for _, d := range densities {
+		variance += (d - mean) * (d - mean)
+	}
+	return variance / float64(len(densities))
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,71 @@
+package biologist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestComplexityEmpty(t *testing.T) {
+	complexity := complexityOf(life.Location{}, life.Dimensions{Width: 16, Height: 16}, []life.Location{})
+	if complexity.BlockEntropy != 0 || complexity.DensityVariance != 0 {
+		t.Errorf("Empty board should not have any entropy or variance: %s\n", complexity.String())
+	}
+}
+
+func TestComplexityBlockEntropy(t *testing.T) {
+	// Two of the four blocks hold a single cell in the same corner
+	dims := life.Dimensions{Width: 4, Height: 4}
+	complexity := complexityOf(life.Location{}, dims, []life.Location{{X: 0, Y: 0}, {X: 2, Y: 0}})
+	if math.Abs(complexity.BlockEntropy-1) > 1e-9 {
+		t.Errorf("Expected a block entropy of 1 but found %f\n", complexity.BlockEntropy)
+	}
+}
+
+func TestComplexityDensityVariance(t *testing.T) {
+	// One of the two regions is full and the other is empty
+	dims := life.Dimensions{Width: 16, Height: 8}
+	full := make([]life.Location, 0)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			full = append(full, life.Location{X: x, Y: y})
+		}
+	}
+
+	complexity := complexityOf(life.Location{}, dims, full)
+	if math.Abs(complexity.DensityVariance-0.25) > 1e-9 {
+		t.Errorf("Expected a density variance of 0.25 but found %f\n", complexity.DensityVariance)
+	}
+}
+
+func TestComplexityCompressibility(t *testing.T) {
+	dims := life.Dimensions{Width: 64, Height: 64}
+	ordered := complexityOf(life.Location{}, dims, life.Blinkers(dims, life.Location{X: 0, Y: 0}))
+
+	random := rand.New(rand.NewSource(1))
+	noise := make([]life.Location, 0)
+	for y := 0; y < dims.Height; y++ {
+		for x := 0; x < dims.Width; x++ {
+			if random.Intn(2) == 0 {
+				noise = append(noise, life.Location{X: x, Y: y})
+			}
+		}
+	}
+	disordered := complexityOf(life.Location{}, dims, noise)
+
+	if ordered.Compressibility >= disordered.Compressibility {
+		t.Errorf("Blinkers (%f) should compress better than noise (%f)\n", ordered.Compressibility, disordered.Compressibility)
+	}
+	if ordered.BlockEntropy >= disordered.BlockEntropy {
+		t.Errorf("Blinkers (%f) should have less block entropy than noise (%f)\n", ordered.BlockEntropy, disordered.BlockEntropy)
+	}
+
+	// The buffers reused between measurements do not carry over any cells
+	small := life.Dimensions{Width: 8, Height: 8}
+	complexityOf(life.Location{}, small, noise)
+	if again := complexityOf(life.Location{}, dims, life.Blinkers(dims, life.Location{X: 0, Y: 0})); again != ordered {
+		t.Errorf("Measuring the blinkers again gave %s instead of %s\n", again.String(), ordered.String())
+	}
+}
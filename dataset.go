@@ -0,0 +1,235 @@
+package biologist
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// GenerationFeatures describes a single analyzed generation of a run
+type GenerationFeatures struct { // {{{
+	ID         string
+	Generation int
+	Population int
+	Births     int
+	Deaths     int
+	MinX       int
+	MinY       int
+	MaxX       int
+	MaxY       int
+	Entropy    float64
+	Complexity
+	Outcome string
+}
+
+var generationFeaturesHeader = []string{"id", "generation", "population", "births", "deaths", "min_x", "min_y", "max_x", "max_y", "entropy", "block_entropy", "compressibility", "density_variance", "outcome"}
+
+func (t *GenerationFeatures) record() []string {
+	return []string{
+		t.ID,
+		strconv.Itoa(t.Generation),
+		strconv.Itoa(t.Population),
+		strconv.Itoa(t.Births),
+		strconv.Itoa(t.Deaths),
+		strconv.Itoa(t.MinX),
+		strconv.Itoa(t.MinY),
+		strconv.Itoa(t.MaxX),
+		strconv.Itoa(t.MaxY),
+		strconv.FormatFloat(t.Entropy, 'f', -1, 64),
+		strconv.FormatFloat(t.BlockEntropy, 'f', -1, 64),
+		strconv.FormatFloat(t.Compressibility, 'f', -1, 64),
+		strconv.FormatFloat(t.DensityVariance, 'f', -1, 64),
+		t.Outcome,
+	}
+} // }}}
+
+// RunFeatures describes a whole run
+type RunFeatures struct { // {{{
+	ID                string
+	Rules             string
+	Width             int
+	Height            int
+	InitialPopulation int
+	Density           float64
+	Entropy           float64
+	PeakPopulation    int
+	Generations       int
+	CycleLength       int
+	Wolfram           string
+	Outcome           string
+}
+
+var runFeaturesHeader = []string{"id", "rules", "width", "height", "initial_population", "density", "entropy", "peak_population", "generations", "cycle_length", "wolfram", "outcome"}
+
+func (t *RunFeatures) record() []string {
+	return []string{
+		t.ID,
+		t.Rules,
+		strconv.Itoa(t.Width),
+		strconv.Itoa(t.Height),
+		strconv.Itoa(t.InitialPopulation),
+		strconv.FormatFloat(t.Density, 'f', -1, 64),
+		strconv.FormatFloat(t.Entropy, 'f', -1, 64),
+		strconv.Itoa(t.PeakPopulation),
+		strconv.Itoa(t.Generations),
+		strconv.Itoa(t.CycleLength),
+		t.Wolfram,
+		t.Outcome,
+	}
+} // }}}
+
+// Dataset holds the features of a set of runs, labeled with how each run turned out
+type Dataset struct { // {{{
+	Runs        []RunFeatures
+	Generations []GenerationFeatures
+}
+
+// WriteRunsCSV writes a row of features per run with a header row
+func (t *Dataset) WriteRunsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write(runFeaturesHeader)
+	for _, run := range t.Runs {
+		writer.Write(run.record())
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteGenerationsCSV writes a row of features per generation with a header row
+func (t *Dataset) WriteGenerationsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write(generationFeaturesHeader)
+	for _, generation := range t.Generations {
+		writer.Write(generation.record())
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteRunsJSONL writes a JSON object of features per run, one per line
+func (t *Dataset) WriteRunsJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, run := range t.Runs {
+		if err := encoder.Encode(run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteGenerationsJSONL writes a JSON object of features per generation, one per line
+func (t *Dataset) WriteGenerationsJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, generation := range t.Generations {
+		if err := encoder.Encode(generation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add extracts the features of the given biologist's run and each of its analyzed generations
+func (t *Dataset) Add(biologist *Biologist) {
+	dims := biologist.Life.Dimensions()
+	// The seed is framed by the dimensions, while later generations of an unbounded board may spread beyond them
+	_, bounds := biologist.extent()
+	area := bounds.Width * bounds.Height
+	analyses := biologist.analyses.GetAll()
+	if len(analyses) == 0 {
+		return
+	}
+
+	outcome := analyses[len(analyses)-1].Status.String()
+	detected, _, cycleLength := biologist.cycle()
+	if detected {
+		outcome = Stable.String()
+	}
+
+	rules := biologist.Rules()
+	run := RunFeatures{
+		ID:                fmt.Sprintf("%x", biologist.ID),
+		Rules:             rules.String(),
+		Width:             dims.Width,
+		Height:            dims.Height,
+		InitialPopulation: len(analyses[0].Living),
+		Density:           density(len(analyses[0].Living), dims.Width*dims.Height),
+		Entropy:           entropy(len(analyses[0].Living), dims.Width*dims.Height),
+		Generations:       len(analyses) - 1,
+		CycleLength:       cycleLength,
+		Wolfram:           biologist.WolframClass().String(),
+		Outcome:           outcome,
+	}
+
+	for generation := range analyses {
+		features := newGenerationFeatures(run.ID, generation, &analyses[generation], area, outcome)
+		if features.Population > run.PeakPopulation {
+			run.PeakPopulation = features.Population
+		}
+
+		t.Generations = append(t.Generations, features)
+	}
+
+	t.Runs = append(t.Runs, run)
+}
+
+// NewDataset extracts the features of each of the given biologists
+func NewDataset(biologists []*Biologist) *Dataset {
+	d := new(Dataset)
+
+	d.Runs = make([]RunFeatures, 0)
+	d.Generations = make([]GenerationFeatures, 0)
+	for _, biologist := range biologists {
+		d.Add(biologist)
+	}
+
+	return d
+} // }}}
+
+func newGenerationFeatures(id string, generation int, analysis *Analysis, area int, outcome string) GenerationFeatures {
+	features := GenerationFeatures{
+		ID:         id,
+		Generation: generation,
+		Population: len(analysis.Living),
+		Entropy:    entropy(len(analysis.Living), area),
+		Complexity: analysis.Complexity,
+		Outcome:    outcome,
+	}
+
+	for _, change := range analysis.Changes {
+		switch change.Change {
+		case Born:
+			features.Births++
+		case Died:
+			features.Deaths++
+		}
+	}
+
+	if len(analysis.Living) > 0 {
+		min, max := bounds(analysis.Living)
+		features.MinX, features.MinY = min.X, min.Y
+		features.MaxX, features.MaxY = max.X, max.Y
+	}
+
+	return features
+}
+
+func density(population, area int) float64 {
+	if area <= 0 {
+		return 0
+	}
+	return float64(population) / float64(area)
+}
+
+// entropy is the Shannon entropy, in bits, of a cell on a board with the given population being alive
+func entropy(population, area int) float64 {
+	p := density(population, area)
+	if p <= 0 || p >= 1 {
+		return 0
+	}
+	return -p*math.Log2(p) - (1-p)*math.Log2(1-p)
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,77 @@
+package biologist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestDataset(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	biologist.Start()
+	<-biologist.Done()
+
+	dataset := NewDataset([]*Biologist{biologist})
+	if len(dataset.Runs) != 1 {
+		t.Fatalf("Expected 1 run but found %d\n", len(dataset.Runs))
+	}
+	run := dataset.Runs[0]
+	if run.Outcome != "Stable" || run.InitialPopulation != 3 || run.CycleLength != 2 {
+		t.Errorf("Unexpected run features: %v\n", run)
+	}
+	if len(dataset.Generations) != biologist.analyses.Count() {
+		t.Fatalf("Expected %d generations but found %d\n", biologist.analyses.Count(), len(dataset.Generations))
+	}
+	if second := dataset.Generations[1]; second.Births != 2 || second.Deaths != 2 || second.Population != 3 {
+		t.Errorf("Unexpected generation features: %v\n", second)
+	}
+
+	var buf bytes.Buffer
+	if err := dataset.WriteGenerationsCSV(&buf); err != nil {
+		t.Fatalf("Unable to write CSV: %s\n", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Unable to read CSV: %s\n", err)
+	}
+	if len(records) != len(dataset.Generations)+1 || len(records[0]) != len(generationFeaturesHeader) {
+		t.Errorf("Unexpected CSV: %v\n", records)
+	}
+
+	buf.Reset()
+	if err := dataset.WriteRunsJSONL(&buf); err != nil {
+		t.Fatalf("Unable to write JSON Lines: %s\n", err)
+	}
+	scanner := bufio.NewScanner(&buf)
+	lines := 0
+	for scanner.Scan() {
+		var decoded RunFeatures
+		if err := json.Unmarshal(scanner.Bytes(), &decoded); err != nil {
+			t.Fatalf("Unable to decode line: %s\n", err)
+		}
+		if decoded.ID != run.ID {
+			t.Errorf("Decoded run %s instead of %s\n", decoded.ID, run.ID)
+		}
+		lines++
+	}
+	if lines != 1 {
+		t.Errorf("Expected 1 line but found %d\n", lines)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	if entropy(0, 4) != 0 || entropy(4, 4) != 0 {
+		t.Error("Empty and full boards should have no entropy")
+	}
+	if entropy(2, 4) != 1 {
+		t.Errorf("Half full board should have an entropy of 1 but found %f\n", entropy(2, 4))
+	}
+}
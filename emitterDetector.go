@@ -0,0 +1,289 @@
+package biologist
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+const (
+	// Longest gun period which will be looked for
+	emitterMaxPeriod = 60
+	// Longest period of any of the ships that will be recognized
+	emitterMaxShipPeriod = 4
+	// Number of ships travelling in the same direction needed before a stream is recognized
+	emitterMinShips = 2
+)
+
+// Emission describes a periodic core which is emitting a stream of ships
+type Emission struct { // {{{
+	Period    int
+	Direction string
+	Ships     int
+	CoreMin   life.Location
+	CoreMax   life.Location
+}
+
+func (t *Emission) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("{Period %d, %d ships heading %s, core ", t.Period, t.Ships, t.Direction))
+	buf.WriteString(t.CoreMin.String())
+	buf.WriteString(" - ")
+	buf.WriteString(t.CoreMax.String())
+	buf.WriteString("}")
+	return buf.String()
+} // }}}
+
+// shapeKey describes the cells of an object relative to its top-left corner
+func shapeKey(cells []life.Location, min life.Location) string {
+	relative := make([]life.Location, len(cells))
+	for i, loc := range cells {
+		relative[i] = life.Location{X: loc.X - min.X, Y: loc.Y - min.Y}
+	}
+	sort.Slice(relative, func(i, j int) bool {
+		if relative[i].Y == relative[j].Y {
+			return relative[i].X < relative[j].X
+		}
+		return relative[i].Y < relative[j].Y
+	})
+
+	var buf bytes.Buffer
+	for _, loc := range relative {
+		buf.WriteString(fmt.Sprintf("%d,%d;", loc.X, loc.Y))
+	}
+	return buf.String()
+}
+
+// heading translates a velocity into a compass direction where north is towards the first row
+func heading(dx, dy int) string {
+	var buf bytes.Buffer
+	if dy < 0 {
+		buf.WriteString("N")
+	} else if dy > 0 {
+		buf.WriteString("S")
+	}
+	if dx > 0 {
+		buf.WriteString("E")
+	} else if dx < 0 {
+		buf.WriteString("W")
+	}
+	return buf.String()
+}
+
+type emitterDetector struct { // {{{
+	log         *log.Logger
+	populations []int
+	recent      [][]life.Location
+	censuses    map[int]Census
+	takeCensus  func([]life.Location) Census
+	Detected    bool
+	Emission    Emission
+}
+
+func (s *emitterDetector) living(generation int) []life.Location {
+	return s.recent[len(s.recent)-1-(len(s.populations)-1-generation)]
+}
+
+func (s *emitterDetector) census(generation int) Census {
+	if census, exists := s.censuses[generation]; exists {
+		return census
+	}
+	census := s.takeCensus(s.living(generation))
+	s.censuses[generation] = census
+	return census
+}
+
+// ships finds the objects of the given generation which reappear translated in a preceding generation
+func (s *emitterDetector) ships(generation int) (map[life.Location]bool, map[life.Location]int) {
+	shipCells := make(map[life.Location]bool)
+	directions := make(map[life.Location]int)
+
+	for _, object := range s.census(generation).Objects {
+		key := shapeKey(object.Cells, object.Min)
+		for k := 1; k <= emitterMaxShipPeriod; k++ {
+			moved := false
+			found := false
+			var velocity life.Location
+			for _, previous := range s.census(generation - k).Objects {
+				if len(previous.Cells) != len(object.Cells) || shapeKey(previous.Cells, previous.Min) != key {
+					continue
+				}
+				dx := object.Min.X - previous.Min.X
+				dy := object.Min.Y - previous.Min.Y
+				if dx == 0 && dy == 0 {
+					// The same object in the same place means it is not going anywhere
+					found = true
+					moved = false
+					break
+				}
+				if dx >= -k && dx <= k && dy >= -k && dy <= k {
+					found = true
+					moved = true
+					velocity = life.Location{X: dx, Y: dy}
+				}
+			}
+			if found {
+				if moved {
+					for _, loc := range object.Cells {
+						shipCells[loc] = true
+					}
+					direction := life.Location{X: sign(velocity.X), Y: sign(velocity.Y)}
+					directions[direction]++
+				}
+				break
+			}
+		}
+	}
+
+	return shipCells, directions
+}
+
+// core returns the living cells which are not part of a ship
+func (s *emitterDetector) core(generation int, shipCells map[life.Location]bool) map[life.Location]bool {
+	core := make(map[life.Location]bool)
+	for _, loc := range s.living(generation) {
+		if !shipCells[loc] {
+			core[loc] = true
+		}
+	}
+	return core
+}
+
+func (s *emitterDetector) periodic(period int) bool {
+	generation := len(s.populations) - 1
+	if generation < period*3 {
+		return false
+	}
+
+	growth := s.populations[generation] - s.populations[generation-period]
+	if growth <= 0 {
+		return false
+	}
+	for i := 1; i < 3; i++ {
+		if s.populations[generation-(period*i)]-s.populations[generation-(period*(i+1))] != growth {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *emitterDetector) analyze(analysis *Analysis, generation int) bool {
+	s.populations = append(s.populations, len(analysis.Living))
+	s.recent = append(s.recent, analysis.Living)
+	if len(s.recent) > emitterMaxPeriod+emitterMaxShipPeriod+1 {
+		s.recent = s.recent[1:]
+	}
+
+	if s.Detected || analysis.Status != Active {
+		return s.Detected
+	}
+
+	s.censuses = make(map[int]Census)
+	for period := 1; period <= emitterMaxPeriod; period++ {
+		if !s.periodic(period) || generation-period < emitterMaxShipPeriod {
+			continue
+		}
+
+		shipCells, directions := s.ships(generation)
+		var direction life.Location
+		ships := 0
+		for dir, count := range directions {
+			if count > ships {
+				direction = dir
+				ships = count
+			}
+		}
+		if ships < emitterMinShips {
+			continue
+		}
+
+		core := s.core(generation, shipCells)
+		if len(core) == 0 {
+			continue
+		}
+
+		previousShipCells, _ := s.ships(generation - period)
+		previousCore := s.core(generation-period, previousShipCells)
+		if len(core) != len(previousCore) {
+			continue
+		}
+		matches := true
+		for loc := range core {
+			if !previousCore[loc] {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+
+		s.Detected = true
+		s.Emission.Period = period
+		s.Emission.Direction = heading(direction.X, direction.Y)
+		s.Emission.Ships = ships
+		first := true
+		for loc := range core {
+			if first || loc.X < s.Emission.CoreMin.X {
+				s.Emission.CoreMin.X = loc.X
+			}
+			if first || loc.Y < s.Emission.CoreMin.Y {
+				s.Emission.CoreMin.Y = loc.Y
+			}
+			if first || loc.X > s.Emission.CoreMax.X {
+				s.Emission.CoreMax.X = loc.X
+			}
+			if first || loc.Y > s.Emission.CoreMax.Y {
+				s.Emission.CoreMax.Y = loc.Y
+			}
+			first = false
+		}
+		s.log.Printf("Found emitter: %s\n", s.Emission.String())
+		break
+	}
+	s.censuses = nil
+
+	return s.Detected
+}
+
+func (s *emitterDetector) String() string {
+	var buf bytes.Buffer
+
+	buf.WriteString("Detected: ")
+	buf.WriteString(fmt.Sprintf("%t\n", s.Detected))
+	if s.Detected {
+		buf.WriteString("Emission: ")
+		buf.WriteString(s.Emission.String())
+		buf.WriteString("\n")
+	}
+
+	return buf.String()
+}
+
+func newEmitterDetector() *emitterDetector {
+	s := new(emitterDetector)
+	s.log = log.New(os.Stdout, "[emitterDetector] ", 0)
+
+	s.populations = make([]int, 0)
+	s.recent = make([][]life.Location, 0)
+	s.takeCensus = takeCensus
+	s.Detected = false
+
+	return s
+} // }}}
+
+func sign(val int) int {
+	if val < 0 {
+		return -1
+	} else if val > 0 {
+		return 1
+	}
+	return 0
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,85 @@
+package biologist
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+var gosperGliderGun = []string{
+	"........................O...........",
+	"......................O.O...........",
+	"............OO......OO............OO",
+	"...........O...O....OO............OO",
+	"OO........O.....O...OO..............",
+	"OO........O...O.OO....O.O...........",
+	"..........O.....O.......O...........",
+	"...........O...O....................",
+	"............OO......................",
+}
+
+func TestHeading(t *testing.T) {
+	headings := map[string]life.Location{
+		"N":  {X: 0, Y: -1},
+		"SE": {X: 1, Y: 1},
+		"W":  {X: -2, Y: 0},
+		"NE": {X: 3, Y: -1},
+	}
+	for expected, velocity := range headings {
+		if dir := heading(velocity.X, velocity.Y); dir != expected {
+			t.Errorf("Expected heading %s for velocity %s but got %s\n", expected, velocity.String(), dir)
+		}
+	}
+}
+
+func TestEmitterDetectorGliderGun(t *testing.T) {
+	size := life.Dimensions{Width: 80, Height: 80}
+	seed := func(dims life.Dimensions, offset life.Location) []life.Location {
+		cells := cellsFromRows(gosperGliderGun)
+		for i := range cells {
+			cells[i].X += offset.X + 1
+			cells[i].Y += offset.Y + 1
+		}
+		return cells
+	}
+
+	biologist, err := New(size, seed, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	biologist.Start()
+	for i := 0; i < 200 && biologist.Emission() == nil; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	biologist.Stop()
+
+	emission := biologist.Emission()
+	if emission == nil {
+		t.Fatalf("Did not detect glider gun after %d generations\n", biologist.analyses.Count())
+	}
+
+	if emission.Period != 30 {
+		t.Errorf("Expected period of 30 but found %d\n", emission.Period)
+	}
+	if emission.Direction != "SE" {
+		t.Errorf("Expected gliders heading SE but found %s\n", emission.Direction)
+	}
+}
+
+func TestEmitterDetectorOscillator(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	biologist.Start()
+	<-biologist.Done()
+	biologist.Stop()
+
+	if biologist.Emission() != nil {
+		t.Fatal("Detected an emitter from a blinker")
+	}
+}
@@ -0,0 +1,543 @@
+package biologist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+const (
+	// Number of best seeds an evolution keeps track of
+	evolutionBestCount = 5
+	// Number of individuals competing for each parent
+	evolutionTournamentSize = 3
+)
+
+type objective int // {{{
+
+const (
+	// LongestLifespan favors seeds which take the most generations to die or stabilize
+	LongestLifespan objective = iota
+	// LargestPopulation favors seeds which leave the most living cells
+	LargestPopulation
+	// MostObjects favors seeds which leave the most distinct kinds of objects
+	MostObjects
+	// SpecificPeriod favors seeds which settle into a cycle of the requested period
+	SpecificPeriod
+)
+
+func (t objective) String() string {
+	switch t {
+	case LongestLifespan:
+		return "LongestLifespan"
+	case LargestPopulation:
+		return "LargestPopulation"
+	case MostObjects:
+		return "MostObjects"
+	case SpecificPeriod:
+		return "SpecificPeriod"
+	}
+
+	return "Unknown"
+}
+
+// ParseObjective returns the objective with the given name
+func ParseObjective(name string) (objective, error) {
+	for o := LongestLifespan; o <= SpecificPeriod; o++ {
+		if o.String() == name {
+			return o, nil
+		}
+	}
+	return LongestLifespan, fmt.Errorf("unknown objective: %s", name)
+} // }}}
+
+// EvolutionOptions configures how seeds are bred and how they are judged
+type EvolutionOptions struct {
+	Dims           life.Dimensions
+	Box            life.Dimensions
+	Rules          Rules
+	Objective      objective
+	Period         int
+	PopulationSize int
+	Generations    int
+	MaxGenerations int
+	MutationRate   float64
+	Elite          int
+	Workers        int
+	Seed           int64
+}
+
+// DefaultEvolutionOptions breeds 32 seeds within an 8x8 box on a 64x64 board for 50 generations,
+// looking for the longest lifespan under Conway's rules
+func DefaultEvolutionOptions() EvolutionOptions {
+	return EvolutionOptions{
+		Dims:           life.Dimensions{Width: 64, Height: 64},
+		Box:            life.Dimensions{Width: 8, Height: 8},
+		Rules:          ConwayRules(),
+		Objective:      LongestLifespan,
+		PopulationSize: 32,
+		Generations:    50,
+		MaxGenerations: 1000,
+		MutationRate:   0.02,
+		Elite:          2,
+		Workers:        4,
+		Seed:           1,
+	}
+}
+
+// genome is the bounding box of a seed, row by row, with living cells marked as 'O' and dead cells as '.'
+type genome []string
+
+func randomGenome(box life.Dimensions, random *rand.Rand) genome {
+	g := make(genome, box.Height)
+	for y := range g {
+		row := make([]byte, box.Width)
+		for x := range row {
+			row[x] = '.'
+			if random.Intn(2) == 0 {
+				row[x] = 'O'
+			}
+		}
+		g[y] = string(row)
+	}
+	return g
+}
+
+// crossover takes each cell from either parent at random
+func crossover(lhs, rhs genome, random *rand.Rand) genome {
+	child := make(genome, len(lhs))
+	for y := range lhs {
+		row := []byte(lhs[y])
+		for x := range row {
+			if random.Intn(2) == 0 {
+				row[x] = rhs[y][x]
+			}
+		}
+		child[y] = string(row)
+	}
+	return child
+}
+
+// mutate flips each cell with the given probability
+func (t genome) mutate(rate float64, random *rand.Rand) genome {
+	mutated := make(genome, len(t))
+	for y := range t {
+		row := []byte(t[y])
+		for x := range row {
+			if random.Float64() < rate {
+				if row[x] == 'O' {
+					row[x] = '.'
+				} else {
+					row[x] = 'O'
+				}
+			}
+		}
+		mutated[y] = string(row)
+	}
+	return mutated
+}
+
+// seed places the living cells of the genome at the center of the board
+func (t genome) seed(dims life.Dimensions) []life.Location {
+	cells := cellsFromRows(t)
+	offsetX := (dims.Width - len(t[0])) / 2
+	offsetY := (dims.Height - len(t)) / 2
+	for i := range cells {
+		cells[i].X += offsetX
+		cells[i].Y += offsetY
+	}
+	return cells
+}
+
+// Candidate is a seed bred by an evolution along with how well it met the objective
+type Candidate struct { // {{{
+	Genome     []string
+	Seed       []life.Location
+	Fitness    float64
+	Generation int
+	Biologist  *Biologist `json:"-"`
+}
+
+func (t *Candidate) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Fitness %.2f (generation %d)", t.Fitness, t.Generation))
+	for _, row := range t.Genome {
+		buf.WriteString("\n")
+		buf.WriteString(row)
+	}
+	return buf.String()
+} // }}}
+
+// fitness scores how well the analysis of a seed met the objective
+func fitness(b *Biologist, opts EvolutionOptions) float64 {
+	last := b.analyses.Get(b.analyses.Count() - 1)
+
+	switch opts.Objective {
+	case LongestLifespan:
+		if lifespan := b.Lifespan(); lifespan != nil {
+			return float64(lifespan.Generations)
+		}
+		return float64(b.analyses.Count() - 1)
+	case LargestPopulation:
+		return float64(len(last.Living))
+	case MostObjects:
+		kinds := make(map[string]bool)
+		for _, object := range b.census(last.Living).Objects {
+			kinds[CanonicalHash(object.Cells)] = true
+		}
+		return float64(len(kinds))
+	case SpecificPeriod:
+		detected, _, length := b.cycle()
+		if !detected || len(last.Living) == 0 {
+			return 0
+		}
+		difference := length - opts.Period
+		if difference < 0 {
+			difference = -difference
+		}
+		return 1 / float64(1+difference)
+	}
+
+	return 0
+}
+
+// evaluate runs the seed of the candidate and scores it
+func evaluate(candidate *Candidate, opts EvolutionOptions) error {
+	candidate.Seed = genome(candidate.Genome).seed(opts.Dims)
+	b, err := NewWithOptions(opts.Dims, Pattern(candidate.Seed), opts.Rules, Options{})
+	if err != nil {
+		return err
+	}
+	runFor(b, opts.MaxGenerations)
+
+	candidate.Biologist = b
+	candidate.Fitness = fitness(b, opts)
+	return nil
+}
+
+// evolutionCheckpoint is what is written out to resume an evolution from
+type evolutionCheckpoint struct {
+	ID         []byte
+	Options    EvolutionOptions
+	Generation int
+	Population []genome
+	Best       []Candidate
+}
+
+// Evolution is a job which breeds seeds to meet an objective, using the analyses of the seeds as their fitness
+type Evolution struct { // {{{
+	ID            []byte
+	Options       EvolutionOptions
+	log           *log.Logger
+	random        *rand.Rand
+	checkpointDir string
+	mutex         sync.RWMutex
+	generation    int
+	population    []genome
+	best          []Candidate
+	discarded     bool
+	finished      bool
+	stop          chan struct{}
+	done          chan struct{}
+	once          sync.Once
+}
+
+// SetCheckpointDir enables writing the population to the given directory after each generation
+func (t *Evolution) SetCheckpointDir(dir string) {
+	t.checkpointDir = dir
+}
+
+// evaluatePopulation scores each individual using a pool of workers
+func (t *Evolution) evaluatePopulation() []Candidate {
+	candidates := make([]Candidate, len(t.population))
+	queue := make(chan int)
+	go func() {
+		defer close(queue)
+		for i := range candidates {
+			queue <- i
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for w := 0; w < t.Options.Workers || w == 0; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range queue {
+				candidates[i].Genome = t.population[i]
+				candidates[i].Generation = t.generation
+				if err := evaluate(&candidates[i], t.Options); err != nil {
+					t.log.Printf("ERROR: Could not evaluate seed: %s\n", err)
+				}
+			}
+		}()
+	}
+	workers.Wait()
+
+	return candidates
+}
+
+// remember keeps the distinct candidates with the highest fitness
+func (t *Evolution) remember(candidates []Candidate) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	seen := make(map[string]bool)
+	best := make([]Candidate, 0)
+	for _, candidate := range append(t.best, candidates...) {
+		key := CanonicalHash(candidate.Seed)
+		if len(candidate.Seed) > 0 && !seen[key] {
+			seen[key] = true
+			best = append(best, candidate)
+		}
+	}
+	sort.SliceStable(best, func(i, j int) bool {
+		return best[i].Fitness > best[j].Fitness
+	})
+	if len(best) > evolutionBestCount {
+		best = best[:evolutionBestCount]
+	}
+	t.best = best
+}
+
+// tournament picks the fittest of a few individuals chosen at random
+func (t *Evolution) tournament(candidates []Candidate) genome {
+	winner := candidates[t.random.Intn(len(candidates))]
+	for i := 1; i < evolutionTournamentSize; i++ {
+		if challenger := candidates[t.random.Intn(len(candidates))]; challenger.Fitness > winner.Fitness {
+			winner = challenger
+		}
+	}
+	return winner.Genome
+}
+
+// breed creates the next population, carrying over the elite unchanged
+func (t *Evolution) breed(candidates []Candidate) []genome {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Fitness > candidates[j].Fitness
+	})
+
+	population := make([]genome, 0, len(candidates))
+	for i := 0; i < t.Options.Elite && i < len(candidates); i++ {
+		population = append(population, candidates[i].Genome)
+	}
+	for len(population) < len(candidates) {
+		child := crossover(t.tournament(candidates), t.tournament(candidates), t.random)
+		population = append(population, child.mutate(t.Options.MutationRate, t.random))
+	}
+	return population
+}
+
+// Start breeds the seeds in the background
+func (t *Evolution) Start() {
+	go func() {
+		defer func() {
+			t.mutex.Lock()
+			t.finished = true
+			if t.discarded {
+				t.removeCheckpoint()
+			}
+			t.mutex.Unlock()
+			close(t.done)
+		}()
+		for t.Generation() < t.Options.Generations {
+			select {
+			case <-t.stop:
+				return
+			default:
+			}
+
+			candidates := t.evaluatePopulation()
+			t.remember(candidates)
+
+			t.mutex.Lock()
+			t.population = t.breed(candidates)
+			t.generation++
+			t.mutex.Unlock()
+
+			if best := t.Best(); len(best) > 0 {
+				t.log.Printf("Generation %d best fitness: %.2f\n", t.Generation(), best[0].Fitness)
+			}
+
+			if t.checkpointDir != "" {
+				if err := t.writeCheckpoint(); err != nil {
+					t.log.Printf("ERROR: Could not checkpoint: %s\n", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the evolution once the current generation has been evaluated
+func (t *Evolution) Stop() {
+	t.once.Do(func() { close(t.stop) })
+}
+
+// discard stops the evolution and removes its checkpoint so that it is not resumed
+func (t *Evolution) discard() {
+	t.mutex.Lock()
+	t.discarded = true
+	if t.finished {
+		t.removeCheckpoint()
+	}
+	t.mutex.Unlock()
+	t.Stop()
+}
+
+// Done returns a channel which is closed once the evolution finishes or is stopped
+func (t *Evolution) Done() <-chan struct{} {
+	return t.done
+}
+
+// Generation returns the number of generations bred so far
+func (t *Evolution) Generation() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.generation
+}
+
+// Best returns the fittest seeds found so far, fittest first
+func (t *Evolution) Best() []Candidate {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	best := make([]Candidate, len(t.best))
+	copy(best, t.best)
+	return best
+}
+
+// biologist returns the analysis of the best seed with the given ID
+func (t *Evolution) biologist(id []byte) *Biologist {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	for _, candidate := range t.best {
+		if candidate.Biologist != nil && bytes.Equal(candidate.Biologist.ID, id) {
+			return candidate.Biologist
+		}
+	}
+	return nil
+}
+
+// Checkpoint writes out the current population and the best seeds so that the evolution can be resumed
+func (t *Evolution) Checkpoint(w io.Writer) error {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return json.NewEncoder(w).Encode(evolutionCheckpoint{
+		ID:         t.ID,
+		Options:    t.Options,
+		Generation: t.generation,
+		Population: t.population,
+		Best:       t.best,
+	})
+}
+
+// writeCheckpoint replaces the checkpoint in the checkpoint directory
+func (t *Evolution) writeCheckpoint() error {
+	file, err := ioutil.TempFile(t.checkpointDir, "evolution")
+	if err != nil {
+		return err
+	}
+	if err := t.Checkpoint(file); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), t.checkpointPath())
+}
+
+// removeCheckpoint deletes the checkpoint from the checkpoint directory, if there is one
+func (t *Evolution) removeCheckpoint() {
+	if t.checkpointDir == "" {
+		return
+	}
+	if err := os.Remove(t.checkpointPath()); err != nil && !os.IsNotExist(err) {
+		t.log.Printf("ERROR: Could not remove checkpoint: %s\n", err)
+	}
+}
+
+// checkpointPath is where the checkpoint is kept in the checkpoint directory
+func (t *Evolution) checkpointPath() string {
+	return filepath.Join(t.checkpointDir, fmt.Sprintf("%x.json", t.ID))
+}
+
+func newEvolution(id []byte, opts EvolutionOptions) *Evolution {
+	e := new(Evolution)
+
+	e.ID = id
+	e.Options = opts
+	e.log = log.New(os.Stdout, fmt.Sprintf("[evolution-%x] ", e.ID), 0)
+	e.random = rand.New(rand.NewSource(opts.Seed))
+	e.best = make([]Candidate, 0)
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+
+	return e
+}
+
+// NewEvolution creates a job which breeds a random population of seeds
+func NewEvolution(opts EvolutionOptions) (*Evolution, error) {
+	if opts.Dims.Width <= 0 || opts.Dims.Height <= 0 || opts.Box.Width <= 0 || opts.Box.Height <= 0 {
+		return nil, errors.New("invalid dimensions")
+	}
+	if opts.Box.Width > opts.Dims.Width || opts.Box.Height > opts.Dims.Height {
+		return nil, errors.New("bounding box does not fit on the board")
+	}
+	if opts.PopulationSize < 2 || opts.MaxGenerations <= 0 {
+		return nil, errors.New("the population needs at least 2 seeds and the generations must be positive")
+	}
+
+	e := newEvolution(uniqueID(), opts)
+	e.population = make([]genome, opts.PopulationSize)
+	for i := range e.population {
+		e.population[i] = randomGenome(opts.Box, e.random)
+	}
+
+	return e, nil
+}
+
+// ResumeEvolution recreates an evolution from a checkpoint, analyzing its best seeds again
+func ResumeEvolution(r io.Reader) (*Evolution, error) {
+	var checkpoint evolutionCheckpoint
+	if err := json.NewDecoder(r).Decode(&checkpoint); err != nil {
+		return nil, err
+	}
+	if len(checkpoint.Population) == 0 {
+		return nil, errors.New("checkpoint has no population")
+	}
+
+	// Continue with different random choices than the ones which led up to the checkpoint
+	opts := checkpoint.Options
+	opts.Seed += int64(checkpoint.Generation)
+
+	e := newEvolution(checkpoint.ID, opts)
+	e.Options.Seed = checkpoint.Options.Seed
+	e.generation = checkpoint.Generation
+	e.population = checkpoint.Population
+	for i := range checkpoint.Best {
+		if err := evaluate(&checkpoint.Best[i], e.Options); err != nil {
+			return nil, err
+		}
+	}
+	e.best = checkpoint.Best
+
+	return e, nil
+} // }}}
+
+// vim: set foldmethod=marker:
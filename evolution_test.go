@@ -0,0 +1,152 @@
+package biologist
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestGenome(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	g := randomGenome(life.Dimensions{Width: 4, Height: 3}, random)
+	if len(g) != 3 || len(g[0]) != 4 {
+		t.Fatalf("Unexpected genome: %v\n", g)
+	}
+
+	if mutated := g.mutate(1, random); len(cellsFromRows(mutated)) != 12-len(cellsFromRows(g)) {
+		t.Errorf("Mutating every cell of %v produced %v\n", g, mutated)
+	}
+	if same := g.mutate(0, random); !sameCells(cellsFromRows(same), cellsFromRows(g)) {
+		t.Errorf("Mutating no cells of %v produced %v\n", g, same)
+	}
+
+	full := genome{"OOOO", "OOOO", "OOOO"}
+	empty := genome{"....", "....", "...."}
+	child := crossover(full, empty, random)
+	if cells := len(cellsFromRows(child)); cells == 0 || cells == 12 {
+		t.Errorf("Crossover only took from one parent: %v\n", child)
+	}
+
+	seed := genome{"O.", ".O"}.seed(life.Dimensions{Width: 6, Height: 6})
+	if !sameCells(seed, []life.Location{{X: 2, Y: 2}, {X: 3, Y: 3}}) {
+		t.Errorf("Seed was not centered: %v\n", seed)
+	}
+}
+
+func TestParseObjective(t *testing.T) {
+	for o := LongestLifespan; o <= SpecificPeriod; o++ {
+		if parsed, err := ParseObjective(o.String()); err != nil || parsed != o {
+			t.Errorf("Could not parse %s\n", o.String())
+		}
+	}
+	if _, err := ParseObjective("Prettiest"); err == nil {
+		t.Error("Parsed an unknown objective")
+	}
+}
+
+func testEvolutionOptions() EvolutionOptions {
+	opts := DefaultEvolutionOptions()
+	opts.Dims = life.Dimensions{Width: 16, Height: 16}
+	opts.Box = life.Dimensions{Width: 4, Height: 4}
+	opts.Objective = LargestPopulation
+	opts.PopulationSize = 6
+	opts.Generations = 3
+	opts.MaxGenerations = 50
+	return opts
+}
+
+func TestEvolution(t *testing.T) {
+	dir, err := ioutil.TempDir("", "evolution")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	evolution, err := NewEvolution(testEvolutionOptions())
+	if err != nil {
+		t.Fatalf("Unable to create evolution: %s\n", err)
+	}
+	evolution.SetCheckpointDir(dir)
+	evolution.Start()
+
+	select {
+	case <-evolution.Done():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Evolution did not finish")
+	}
+
+	if evolution.Generation() != 3 {
+		t.Errorf("Bred %d generations instead of 3\n", evolution.Generation())
+	}
+
+	best := evolution.Best()
+	if len(best) == 0 {
+		t.Fatal("Did not find any seeds")
+	}
+	for i, candidate := range best {
+		if candidate.Biologist == nil {
+			t.Errorf("Seed %d is missing its analysis\n", i)
+		} else if float64(len(candidate.Biologist.analyses.Get(candidate.Biologist.analyses.Count()-1).Living)) != candidate.Fitness {
+			t.Errorf("Fitness of seed %d does not match its final population\n", i)
+		}
+		if i > 0 && candidate.Fitness > best[i-1].Fitness {
+			t.Errorf("Seeds are not ordered by fitness: %v\n", best)
+		}
+	}
+
+	file, err := os.Open(filepath.Join(dir, fmt.Sprintf("%x", evolution.ID)+".json"))
+	if err != nil {
+		t.Fatalf("Evolution was not checkpointed: %s\n", err)
+	}
+	defer file.Close()
+
+	resumed, err := ResumeEvolution(file)
+	if err != nil {
+		t.Fatalf("Unable to resume evolution: %s\n", err)
+	}
+	if !bytes.Equal(resumed.ID, evolution.ID) || resumed.Generation() != 3 || len(resumed.Best()) != len(best) {
+		t.Errorf("Resumed evolution does not match the checkpoint\n")
+	}
+	if resumed.Best()[0].Fitness != best[0].Fitness || resumed.Best()[0].Biologist == nil {
+		t.Errorf("Best seed was not analyzed again when resumed\n")
+	}
+
+	mgr := NewManager()
+	mgr.AddEvolution(evolution)
+	mgr.RemoveEvolution(evolution.ID)
+	if mgr.Evolution(evolution.ID) != nil {
+		t.Error("Manager did not forget the removed evolution")
+	}
+	if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("%x", evolution.ID)+".json")); !os.IsNotExist(err) {
+		t.Error("Checkpoint of the removed evolution was not removed")
+	}
+}
+
+func TestSpecificPeriodFitness(t *testing.T) {
+	opts := testEvolutionOptions()
+	opts.Objective = SpecificPeriod
+	opts.Period = 2
+
+	blinker := Candidate{Genome: []string{".O.", ".O.", ".O."}}
+	if err := evaluate(&blinker, opts); err != nil {
+		t.Fatalf("Unable to evaluate: %s\n", err)
+	}
+	if blinker.Fitness != 1 {
+		t.Errorf("Blinker has fitness %f for a period of 2\n", blinker.Fitness)
+	}
+
+	block := Candidate{Genome: []string{"OO", "OO"}}
+	if err := evaluate(&block, opts); err != nil {
+		t.Fatalf("Unable to evaluate: %s\n", err)
+	}
+	if block.Fitness != 0.5 {
+		t.Errorf("Block has fitness %f for a period of 2\n", block.Fitness)
+	}
+}
@@ -0,0 +1,350 @@
+package biologist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+type ruleBehavior int // {{{
+
+const (
+	// RuleDies applies to rules under which soups typically lose all of their living cells
+	RuleDies ruleBehavior = iota
+	// RuleStable applies to rules under which soups typically settle into still lifes and oscillators
+	RuleStable
+	// RuleChaotic applies to rules under which soups typically keep changing without filling the board
+	RuleChaotic
+	// RuleExplosive applies to rules under which soups typically keep growing
+	RuleExplosive
+	numRuleBehaviors
+)
+
+func (t ruleBehavior) String() string {
+	switch t {
+	case RuleDies:
+		return "Dies"
+	case RuleStable:
+		return "Stable"
+	case RuleChaotic:
+		return "Chaotic"
+	case RuleExplosive:
+		return "Explosive"
+	}
+
+	return "Unknown"
+} // }}}
+
+// RuleFilter selects the rules to explore by the neighbor counts they must and must not include
+type RuleFilter struct { // {{{
+	Born       []int
+	Survive    []int
+	NotBorn    []int
+	NotSurvive []int
+}
+
+func (t *RuleFilter) matches(born, survive [maxNeighbors + 1]bool) bool {
+	for _, num := range t.Born {
+		if num < 0 || num > maxNeighbors || !born[num] {
+			return false
+		}
+	}
+	for _, num := range t.Survive {
+		if num < 0 || num > maxNeighbors || !survive[num] {
+			return false
+		}
+	}
+	for _, num := range t.NotBorn {
+		if num >= 0 && num <= maxNeighbors && born[num] {
+			return false
+		}
+	}
+	for _, num := range t.NotSurvive {
+		if num >= 0 && num <= maxNeighbors && survive[num] {
+			return false
+		}
+	}
+	return true
+} // }}}
+
+// EnumerateRules lists each of the outer-totalistic rules which matches the filter
+func EnumerateRules(filter RuleFilter) []Rules {
+	all := make([]Rules, 0)
+	for mask := 0; mask < 1<<(2*(maxNeighbors+1)); mask++ {
+		var born, survive [maxNeighbors + 1]bool
+		for num := 0; num <= maxNeighbors; num++ {
+			born[num] = mask&(1<<uint(num)) != 0
+			survive[num] = mask&(1<<uint(maxNeighbors+1+num)) != 0
+		}
+		if !filter.matches(born, survive) {
+			continue
+		}
+
+		rules := Rules{Born: make([]int, 0), Survive: make([]int, 0)}
+		for num := 0; num <= maxNeighbors; num++ {
+			if born[num] {
+				rules.Born = append(rules.Born, num)
+			}
+			if survive[num] {
+				rules.Survive = append(rules.Survive, num)
+			}
+		}
+		all = append(all, rules)
+	}
+	return all
+}
+
+// ExplorerOptions configures the batch of soups each rule is tried with
+type ExplorerOptions struct {
+	Dims           life.Dimensions
+	Soups          int
+	Density        int
+	MaxGenerations int
+	Seed           int64
+	Workers        int
+}
+
+// DefaultExplorerOptions tries 8 soups at a density of 30% on 32x32 boards for up to 500 generations
+func DefaultExplorerOptions() ExplorerOptions {
+	return ExplorerOptions{
+		Dims:           life.Dimensions{Width: 32, Height: 32},
+		Soups:          8,
+		Density:        30,
+		MaxGenerations: 500,
+		Seed:           1,
+		Workers:        4,
+	}
+}
+
+// soup fills the board at random to the given percentage
+func soup(dims life.Dimensions, density int, random *rand.Rand) []life.Location {
+	cells := make([]life.Location, 0)
+	for y := 0; y < dims.Height; y++ {
+		for x := 0; x < dims.Width; x++ {
+			if random.Intn(100) < density {
+				cells = append(cells, life.Location{X: x, Y: y})
+			}
+		}
+	}
+	return cells
+}
+
+// RuleResult summarizes how the batch of soups behaved under a rule
+type RuleResult struct { // {{{
+	Rules           string
+	Behavior        string
+	Soups           map[string]int
+	MeanGenerations float64
+	MeanDensity     float64
+}
+
+func (t *RuleResult) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("%s: %s", t.Rules, t.Behavior))
+	for behavior := RuleDies; behavior < numRuleBehaviors; behavior++ {
+		buf.WriteString(fmt.Sprintf(" %s=%d", behavior.String(), t.Soups[behavior.String()]))
+	}
+	return buf.String()
+} // }}}
+
+// runFor analyzes the biologist until it is no longer active or has reached the given number of generations.
+// The analysis stops itself at either point, so its results can be read once this returns
+func runFor(b *Biologist, maxGenerations int) {
+	if maxGenerations <= 0 {
+		return
+	}
+
+	// Analyzing any generations beyond the maximum would make the results depend on how soon it is stopped
+	b.limit = maxGenerations
+	b.Start()
+	b.wait()
+}
+
+// runSoup analyzes the seed until it is no longer active or has reached the maximum number of generations
+func runSoup(rules Rules, seed []life.Location, opts ExplorerOptions) (ruleBehavior, int, float64, error) {
+	b, err := NewWithOptions(opts.Dims, Pattern(seed), rules, Options{})
+	if err != nil {
+		return RuleDies, 0, 0, err
+	}
+
+	runFor(b, opts.MaxGenerations)
+
+	last := b.analyses.Get(b.analyses.Count() - 1)
+	generations := b.analyses.Count() - 1
+	finalDensity := density(len(last.Living), opts.Dims.Width*opts.Dims.Height)
+
+	detected, cycleStart, _ := b.cycle()
+	switch {
+	case last.Status == Dead:
+		return RuleDies, generations, finalDensity, nil
+	case last.Status == Emitting:
+		// Guns and puffers keep adding cells
+		return RuleExplosive, generations, finalDensity, nil
+	case detected:
+		return RuleStable, cycleStart, finalDensity, nil
+	}
+
+	growth := b.Growth()
+	if growth.Class == LinearGrowth || growth.Class == QuadraticGrowth || len(last.Living) > 2*len(seed) {
+		return RuleExplosive, generations, finalDensity, nil
+	}
+	return RuleChaotic, generations, finalDensity, nil
+}
+
+// ExploreRule runs the batch of soups under the rule and classifies its typical behavior
+func ExploreRule(rules Rules, opts ExplorerOptions) (RuleResult, error) {
+	result := RuleResult{Rules: rules.String(), Soups: make(map[string]int)}
+	if opts.Soups <= 0 {
+		return result, errors.New("no soups to explore with")
+	}
+
+	// Every rule sees the same batch of soups
+	random := rand.New(rand.NewSource(opts.Seed))
+
+	var counts [numRuleBehaviors]int
+	for i := 0; i < opts.Soups; i++ {
+		behavior, generations, finalDensity, err := runSoup(rules, soup(opts.Dims, opts.Density, random), opts)
+		if err != nil {
+			return result, err
+		}
+		counts[behavior]++
+		result.MeanGenerations += float64(generations) / float64(opts.Soups)
+		result.MeanDensity += finalDensity / float64(opts.Soups)
+	}
+
+	typical := RuleDies
+	for behavior := RuleDies; behavior < numRuleBehaviors; behavior++ {
+		result.Soups[behavior.String()] = counts[behavior]
+		if counts[behavior] > counts[typical] {
+			typical = behavior
+		}
+	}
+	result.Behavior = typical.String()
+
+	return result, nil
+}
+
+// Explorer is a job which sweeps a set of rules, classifying each one by how soups behave under it
+type Explorer struct { // {{{
+	ID      []byte
+	Options ExplorerOptions
+	log     *log.Logger
+	rules   []Rules
+	catalog *Catalog
+	mutex   sync.RWMutex
+	results []RuleResult
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// SetCatalog enables recording the result of each rule explored from now on
+func (t *Explorer) SetCatalog(catalog *Catalog) {
+	t.catalog = catalog
+}
+
+// Start sweeps the rules in the background
+func (t *Explorer) Start() {
+	queue := make(chan Rules)
+	go func() {
+		defer close(queue)
+		for _, rules := range t.rules {
+			select {
+			case queue <- rules:
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for i := 0; i < t.Options.Workers || i == 0; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for rules := range queue {
+				result, err := ExploreRule(rules, t.Options)
+				if err != nil {
+					t.log.Printf("ERROR: Could not explore %s: %s\n", rules.String(), err)
+					continue
+				}
+
+				if t.catalog != nil {
+					if err := t.catalog.RecordRule(t.ID, result); err != nil {
+						t.log.Printf("ERROR: Could not catalog %s: %s\n", rules.String(), err)
+					}
+				}
+
+				t.mutex.Lock()
+				t.results = append(t.results, result)
+				t.mutex.Unlock()
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		t.log.Printf("Explored %d of %d rules\n", len(t.Results()), len(t.rules))
+		close(t.done)
+	}()
+}
+
+// Stop ends the sweep once the rules being explored are finished
+func (t *Explorer) Stop() {
+	t.once.Do(func() { close(t.stop) })
+}
+
+// Done returns a channel which is closed once the sweep finishes or is stopped
+func (t *Explorer) Done() <-chan struct{} {
+	return t.done
+}
+
+// Progress returns the number of rules explored so far and the number of rules in the sweep
+func (t *Explorer) Progress() (int, int) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return len(t.results), len(t.rules)
+}
+
+// Results returns the result of each rule explored so far
+func (t *Explorer) Results() []RuleResult {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	results := make([]RuleResult, len(t.results))
+	copy(results, t.results)
+	return results
+}
+
+// NewExplorer creates a job which explores each rule matching the filter
+func NewExplorer(filter RuleFilter, opts ExplorerOptions) (*Explorer, error) {
+	if opts.Dims.Width <= 0 || opts.Dims.Height <= 0 {
+		return nil, errors.New("invalid dimensions")
+	}
+	if opts.Soups <= 0 || opts.MaxGenerations <= 0 {
+		return nil, errors.New("the number of soups and generations must be positive")
+	}
+
+	e := new(Explorer)
+
+	e.ID = uniqueID()
+	e.Options = opts
+	e.log = log.New(os.Stdout, fmt.Sprintf("[explorer-%x] ", e.ID), 0)
+	e.rules = EnumerateRules(filter)
+	if len(e.rules) == 0 {
+		return nil, errors.New("no rules match the filter")
+	}
+	e.results = make([]RuleResult, 0)
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+
+	return e, nil
+} // }}}
+
+// vim: set foldmethod=marker:
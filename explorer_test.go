@@ -0,0 +1,114 @@
+package biologist
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestEnumerateRules(t *testing.T) {
+	if all := EnumerateRules(RuleFilter{}); len(all) != 1<<18 {
+		t.Errorf("Expected %d rules but found %d\n", 1<<18, len(all))
+	}
+
+	filter := RuleFilter{
+		Born:       []int{3},
+		NotBorn:    []int{0, 1, 2, 4, 5, 6, 7, 8},
+		Survive:    []int{2, 3},
+		NotSurvive: []int{0, 1, 4, 5, 6, 7},
+	}
+	rules := EnumerateRules(filter)
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules but found %d\n", len(rules))
+	}
+	if rules[0].String() != "B3/S23" || rules[1].String() != "B3/S238" {
+		t.Errorf("Unexpected rules: %s, %s\n", rules[0].String(), rules[1].String())
+	}
+}
+
+func TestExploreRule(t *testing.T) {
+	opts := ExplorerOptions{Dims: life.Dimensions{Width: 16, Height: 16}, Soups: 3, Density: 30, MaxGenerations: 100, Seed: 1}
+
+	// Nothing is born and nothing survives
+	result, err := ExploreRule(Rules{Born: []int{}, Survive: []int{}}, opts)
+	if err != nil {
+		t.Fatalf("Unable to explore rule: %s\n", err)
+	}
+	if result.Behavior != "Dies" || result.Soups["Dies"] != 3 || result.MeanGenerations != 1 {
+		t.Errorf("Unexpected result: %s\n", result.String())
+	}
+
+	// Everything survives and nothing is born
+	result, err = ExploreRule(Rules{Born: []int{}, Survive: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}}, opts)
+	if err != nil {
+		t.Fatalf("Unable to explore rule: %s\n", err)
+	}
+	if result.Behavior != "Stable" {
+		t.Errorf("Unexpected result: %s\n", result.String())
+	}
+
+	if _, err := ExploreRule(ConwayRules(), ExplorerOptions{}); err == nil {
+		t.Error("Explored without any soups")
+	}
+}
+
+func TestExplorer(t *testing.T) {
+	catalog, dir := tempCatalog(t)
+	defer os.RemoveAll(dir)
+
+	filter := RuleFilter{
+		Born:       []int{3},
+		NotBorn:    []int{0, 1, 2, 4, 5, 6, 7, 8},
+		Survive:    []int{2, 3},
+		NotSurvive: []int{0, 1, 4, 5, 6, 7},
+	}
+	opts := ExplorerOptions{Dims: life.Dimensions{Width: 16, Height: 16}, Soups: 2, Density: 30, MaxGenerations: 100, Seed: 1, Workers: 2}
+	explorer, err := NewExplorer(filter, opts)
+	if err != nil {
+		t.Fatalf("Unable to create explorer: %s\n", err)
+	}
+
+	mgr := NewManager()
+	mgr.SetCatalog(catalog)
+	mgr.AddExplorer(explorer)
+	if mgr.Explorer(explorer.ID) != explorer {
+		t.Fatal("Manager did not keep track of the explorer")
+	}
+
+	explorer.Start()
+	select {
+	case <-explorer.Done():
+	case <-time.After(time.Second * 10):
+		t.Fatal("Explorer did not finish")
+	}
+
+	if explored, total := explorer.Progress(); explored != 2 || total != 2 {
+		t.Errorf("Explored %d of %d rules\n", explored, total)
+	}
+
+	results, err := catalog.SearchRules("", 0)
+	if err != nil {
+		t.Fatalf("Unable to search catalog: %s\n", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 cataloged rules but found %d\n", len(results))
+	}
+	total := 0
+	for _, count := range results[0].Soups {
+		total += count
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 soups per rule but found %d\n", total)
+	}
+
+	mgr.RemoveExplorer(explorer.ID)
+	if mgr.Explorer(explorer.ID) != nil {
+		t.Error("Manager did not forget the removed explorer")
+	}
+
+	if _, err := NewExplorer(RuleFilter{Born: []int{3}, NotBorn: []int{3}}, opts); err == nil {
+		t.Error("Created explorer without any rules")
+	}
+}
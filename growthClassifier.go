@@ -0,0 +1,195 @@
+package biologist
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+const (
+	// Number of generations which need to be analyzed before any classification is attempted
+	growthMinGenerations = 50
+	// Number of generations between each classification
+	growthInterval = 10
+	// Maximum change relative to the mean, across the trend window, for a value to be considered flat
+	growthFlatThreshold = 0.1
+	// Minimum ratio between the growth rates of the second and first half of the trend window for quadratic growth
+	growthQuadraticRatio = 1.2
+)
+
+type growthClass int // {{{
+
+const (
+	// Undetermined applies to a trajectory which has not been classified
+	Undetermined growthClass = iota
+	// LinearGrowth applies to a trajectory which gains a steady number of cells each generation
+	LinearGrowth
+	// QuadraticGrowth applies to a trajectory which gains more cells each generation than the last
+	QuadraticGrowth
+	// BoundedChaos applies to a trajectory which keeps changing without its population or area trending
+	BoundedChaos
+)
+
+func (t growthClass) String() string {
+	switch t {
+	case Undetermined:
+		return "Undetermined"
+	case LinearGrowth:
+		return "LinearGrowth"
+	case QuadraticGrowth:
+		return "QuadraticGrowth"
+	case BoundedChaos:
+		return "BoundedChaos"
+	}
+
+	return "Unknown"
+} // }}}
+
+// Growth is the classification of the trajectory of an analysis
+type Growth struct { // {{{
+	Class      growthClass
+	Confidence float64
+	Generation int
+}
+
+func (t *Growth) String() string {
+	return fmt.Sprintf("{%s, %.2f, generation %d}", t.Class.String(), t.Confidence, t.Generation)
+} // }}}
+
+// trend fits a line to the given values and returns its slope and coefficient of determination
+func trend(values []float64) (float64, float64) {
+	n := float64(len(values))
+	if n < 2 {
+		return 0, 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range values {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, 0
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+
+	mean := sumY / n
+	var residuals, total float64
+	for i, y := range values {
+		fit := intercept + slope*float64(i)
+		residuals += (y - fit) * (y - fit)
+		total += (y - mean) * (y - mean)
+	}
+	if total == 0 {
+		return slope, 1
+	}
+
+	return slope, 1 - (residuals / total)
+}
+
+// relativeChange returns how much the trend line changes across the values relative to their mean
+func relativeChange(values []float64) float64 {
+	var sum float64
+	for _, val := range values {
+		sum += val
+	}
+	if sum == 0 {
+		return 0
+	}
+	slope, _ := trend(values)
+	return slope * float64(len(values)) / (sum / float64(len(values)))
+}
+
+type growthClassifier struct { // {{{
+	populations []float64
+	areas       []float64
+	Growth      Growth
+}
+
+func (s *growthClassifier) classify(generation int) {
+	// Only the latter half of the trajectory is used to avoid the initial transients
+	start := len(s.populations) / 2
+	populations := s.populations[start:]
+	areas := s.areas[start:]
+
+	s.Growth.Generation = generation
+
+	populationChange := relativeChange(populations)
+	areaChange := relativeChange(areas)
+	if math.Abs(populationChange) < growthFlatThreshold && math.Abs(areaChange) < growthFlatThreshold {
+		s.Growth.Class = BoundedChaos
+		s.Growth.Confidence = 1 - (math.Max(math.Abs(populationChange), math.Abs(areaChange)) / growthFlatThreshold)
+		return
+	}
+
+	half := len(populations) / 2
+	earlySlope, _ := trend(populations[:half])
+	lateSlope, _ := trend(populations[half:])
+	if populationChange < growthFlatThreshold || earlySlope <= 0 {
+		s.Growth.Class = Undetermined
+		s.Growth.Confidence = 0
+		return
+	}
+
+	if lateSlope/earlySlope >= growthQuadraticRatio {
+		// Quadratic growth means that the square root of the population grows linearly
+		roots := make([]float64, len(populations))
+		for i, population := range populations {
+			roots[i] = math.Sqrt(population)
+		}
+		_, fit := trend(roots)
+		s.Growth.Class = QuadraticGrowth
+		s.Growth.Confidence = fit
+	} else {
+		_, fit := trend(populations)
+		s.Growth.Class = LinearGrowth
+		s.Growth.Confidence = fit
+	}
+}
+
+func (s *growthClassifier) analyze(analysis *Analysis, generation int) Growth {
+	s.populations = append(s.populations, float64(len(analysis.Living)))
+
+	min, max := bounds(analysis.Living)
+	area := 0
+	if len(analysis.Living) > 0 {
+		area = (max.X - min.X + 1) * (max.Y - min.Y + 1)
+	}
+	s.areas = append(s.areas, float64(area))
+
+	if analysis.Status == Active && len(s.populations) >= growthMinGenerations && generation%growthInterval == 0 {
+		s.classify(generation)
+	}
+
+	return s.Growth
+}
+
+func (s *growthClassifier) String() string {
+	var buf bytes.Buffer
+
+	buf.WriteString("Generations: ")
+	buf.WriteString(fmt.Sprintf("%d\n", len(s.populations)))
+	buf.WriteString("Growth: ")
+	buf.WriteString(s.Growth.String())
+	buf.WriteString("\n")
+
+	return buf.String()
+}
+
+func newGrowthClassifier() *growthClassifier {
+	s := new(growthClassifier)
+
+	s.populations = make([]float64, 0)
+	s.areas = make([]float64, 0)
+	s.Growth.Class = Undetermined
+
+	return s
+} // }}}
+
+// vim: set foldmethod=marker:
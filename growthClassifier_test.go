@@ -0,0 +1,73 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func growthAnalysis(population int) *Analysis {
+	analysis := &Analysis{Status: Active, Living: make([]life.Location, population)}
+	for i := range analysis.Living {
+		analysis.Living[i] = life.Location{X: i % 100, Y: i / 100}
+	}
+	return analysis
+}
+
+func TestTrend(t *testing.T) {
+	slope, fit := trend([]float64{1, 3, 5, 7, 9})
+	if slope != 2 {
+		t.Errorf("Expected slope of 2 but found %f\n", slope)
+	}
+	if fit != 1 {
+		t.Errorf("Expected perfect fit but found %f\n", fit)
+	}
+}
+
+func TestGrowthClassifierLinear(t *testing.T) {
+	classifier := newGrowthClassifier()
+	for gen := 0; gen <= 200; gen++ {
+		classifier.analyze(growthAnalysis(10+(gen*5)), gen)
+	}
+
+	if classifier.Growth.Class != LinearGrowth {
+		t.Fatalf("Expected linear growth but classified as %s\n", classifier.Growth.String())
+	}
+	if classifier.Growth.Confidence < 0.9 {
+		t.Errorf("Unexpectedly low confidence: %s\n", classifier.Growth.String())
+	}
+}
+
+func TestGrowthClassifierQuadratic(t *testing.T) {
+	classifier := newGrowthClassifier()
+	for gen := 0; gen <= 200; gen++ {
+		classifier.analyze(growthAnalysis(10+(gen*gen)), gen)
+	}
+
+	if classifier.Growth.Class != QuadraticGrowth {
+		t.Fatalf("Expected quadratic growth but classified as %s\n", classifier.Growth.String())
+	}
+}
+
+func TestGrowthClassifierBoundedChaos(t *testing.T) {
+	classifier := newGrowthClassifier()
+	for gen := 0; gen <= 200; gen++ {
+		analysis := growthAnalysis(500 + (gen%7)*3)
+		classifier.analyze(analysis, gen)
+	}
+
+	if classifier.Growth.Class != BoundedChaos {
+		t.Fatalf("Expected bounded chaos but classified as %s\n", classifier.Growth.String())
+	}
+}
+
+func TestGrowthClassifierTooEarly(t *testing.T) {
+	classifier := newGrowthClassifier()
+	for gen := 0; gen < growthMinGenerations-1; gen++ {
+		classifier.analyze(growthAnalysis(10+(gen*5)), gen)
+	}
+
+	if classifier.Growth.Class != Undetermined {
+		t.Fatalf("Classified growth before enough generations were analyzed: %s\n", classifier.Growth.String())
+	}
+}
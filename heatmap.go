@@ -0,0 +1,83 @@
+package biologist
+
+import (
+	"gitlab.com/hokiegeek/life"
+)
+
+// Heatmap counts, for each location of the board, how many generations it was born into, died in or was alive for.
+// Each grid is indexed by row and then by column
+type Heatmap struct { // {{{
+	// Origin is the location counted in the first column of the first row
+	Origin      life.Location
+	Dims        life.Dimensions
+	Generations int
+	Born        [][]int
+	Died        [][]int
+	Alive       [][]int
+}
+
+func newGrid(dims life.Dimensions) [][]int {
+	grid := make([][]int, dims.Height)
+	for y := range grid {
+		grid[y] = make([]int, dims.Width)
+	}
+	return grid
+}
+
+func (t *Heatmap) increment(grid [][]int, loc life.Location) {
+	x, y := loc.X-t.Origin.X, loc.Y-t.Origin.Y
+	if x >= 0 && y >= 0 && x < t.Dims.Width && y < t.Dims.Height {
+		grid[y][x]++
+	}
+}
+
+func (t *Heatmap) add(analysis *Analysis) {
+	t.Generations++
+	for _, loc := range analysis.Living {
+		t.increment(t.Alive, loc)
+	}
+	for _, change := range analysis.Changes {
+		switch change.Change {
+		case Born:
+			t.increment(t.Born, change.Location)
+		case Died:
+			t.increment(t.Died, change.Location)
+		}
+	}
+}
+
+// Max returns the highest count in the given grid
+func (t *Heatmap) Max(grid [][]int) int {
+	max := 0
+	for _, row := range grid {
+		for _, count := range row {
+			if count > max {
+				max = count
+			}
+		}
+	}
+	return max
+}
+
+func newHeatmap(origin life.Location, dims life.Dimensions) *Heatmap {
+	h := new(Heatmap)
+
+	h.Origin = origin
+	h.Dims = dims
+	h.Born = newGrid(dims)
+	h.Died = newGrid(dims)
+	h.Alive = newGrid(dims)
+
+	return h
+} // }}}
+
+// Heatmap accumulates the activity of every analyzed generation
+func (t *Biologist) Heatmap() *Heatmap {
+	heatmap := newHeatmap(t.extent())
+	for _, analysis := range t.analyses.GetAll() {
+		heatmap.add(&analysis)
+	}
+	return heatmap
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,56 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestHeatmapAdd(t *testing.T) {
+	heatmap := newHeatmap(life.Location{}, life.Dimensions{Width: 3, Height: 3})
+
+	heatmap.add(&Analysis{
+		Living:  []life.Location{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}},
+		Changes: []changedLocation{{Location: life.Location{X: 1, Y: 0}, Change: Born}},
+	})
+	heatmap.add(&Analysis{
+		Living: []life.Location{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 7, Y: 7}},
+		Changes: []changedLocation{
+			{Location: life.Location{X: 1, Y: 0}, Change: Died},
+			{Location: life.Location{X: 0, Y: 1}, Change: Born},
+		},
+	})
+
+	if heatmap.Generations != 2 {
+		t.Errorf("Expected 2 generations but found %d\n", heatmap.Generations)
+	}
+	if heatmap.Alive[1][1] != 2 || heatmap.Max(heatmap.Alive) != 2 {
+		t.Errorf("Expected center to be alive for 2 generations but found %d\n", heatmap.Alive[1][1])
+	}
+	if heatmap.Born[0][1] != 1 || heatmap.Born[1][0] != 1 {
+		t.Errorf("Unexpected births: %v\n", heatmap.Born)
+	}
+	if heatmap.Died[0][1] != 1 || heatmap.Max(heatmap.Died) != 1 {
+		t.Errorf("Unexpected deaths: %v\n", heatmap.Died)
+	}
+}
+
+func TestBiologistHeatmap(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	biologist.Start()
+	<-biologist.Done()
+	biologist.Stop()
+
+	heatmap := biologist.Heatmap()
+	if heatmap.Generations != biologist.analyses.Count() {
+		t.Fatalf("Expected %d generations in the heatmap but found %d\n", biologist.analyses.Count(), heatmap.Generations)
+	}
+	if len(heatmap.Alive) != size.Height || len(heatmap.Alive[0]) != size.Width {
+		t.Fatal("Heatmap grid does not match the board dimensions")
+	}
+}
@@ -0,0 +1,137 @@
+package biologist
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+const (
+	life105Header = "#Life 1.05"
+	life106Header = "#Life 1.06"
+)
+
+// ReadLife106 parses a pattern in the Life 1.06 format, which lists the coordinates of each living cell
+func ReadLife106(r io.Reader) ([]life.Location, error) {
+	cells := make([]life.Location, 0)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var loc life.Location
+		if _, err := fmt.Sscanf(line, "%d %d", &loc.X, &loc.Y); err != nil {
+			return nil, fmt.Errorf("invalid coordinates '%s': %s", line, err)
+		}
+		cells = append(cells, loc)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cells, nil
+}
+
+// WriteLife106 writes the coordinates of the given cells in the Life 1.06 format
+func WriteLife106(w io.Writer, cells []life.Location) error {
+	sorted := make([]life.Location, len(cells))
+	copy(sorted, cells)
+	sortLocations(sorted)
+
+	var buf bytes.Buffer
+	buf.WriteString(life106Header)
+	buf.WriteString("\n")
+	for _, loc := range sorted {
+		buf.WriteString(fmt.Sprintf("%d %d\n", loc.X, loc.Y))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadLife105 parses a pattern in the Life 1.05 format, which is made up of blocks of cells
+// placed at the coordinates given in each "#P" line. If the pattern does not specify any
+// rules, then the rules of Conway's Game of Life are assumed
+func ReadLife105(r io.Reader) ([]life.Location, Rules, error) {
+	cells := make([]life.Location, 0)
+	rules := ConwayRules()
+
+	scanner := bufio.NewScanner(r)
+	var origin life.Location
+	y := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case len(line) == 0:
+			continue
+		case strings.HasPrefix(line, "#P"):
+			if _, err := fmt.Sscanf(line, "#P %d %d", &origin.X, &origin.Y); err != nil {
+				return nil, rules, fmt.Errorf("invalid block position '%s': %s", line, err)
+			}
+			y = 0
+		case strings.HasPrefix(line, "#N"):
+			rules = ConwayRules()
+		case strings.HasPrefix(line, "#R"):
+			var err error
+			if rules, err = ParseRules(strings.TrimSpace(strings.TrimPrefix(line, "#R"))); err != nil {
+				return nil, rules, err
+			}
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			for x, c := range line {
+				switch c {
+				case '*':
+					cells = append(cells, life.Location{X: origin.X + x, Y: origin.Y + y})
+				case '.':
+				default:
+					return nil, rules, fmt.Errorf("invalid character '%c' in block row %d", c, y)
+				}
+			}
+			y++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, rules, err
+	}
+
+	return cells, rules, nil
+}
+
+// WriteLife105 writes the given cells as a single block in the Life 1.05 format
+func WriteLife105(w io.Writer, cells []life.Location, rules Rules) error {
+	min, max := bounds(cells)
+
+	alive := make(map[life.Location]bool)
+	for _, loc := range cells {
+		alive[loc] = true
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(life105Header)
+	buf.WriteString("\n")
+	buf.WriteString(fmt.Sprintf("#R %s\n", rules.survivalBirth()))
+	if len(cells) > 0 {
+		buf.WriteString(fmt.Sprintf("#P %d %d\n", min.X, min.Y))
+		for y := min.Y; y <= max.Y; y++ {
+			for x := min.X; x <= max.X; x++ {
+				if alive[life.Location{X: x, Y: y}] {
+					buf.WriteString("*")
+				} else {
+					buf.WriteString(".")
+				}
+			}
+			buf.WriteString("\n")
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,87 @@
+package biologist
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestLife106RoundTrip(t *testing.T) {
+	glider := []life.Location{{X: -1, Y: -1}, {X: 0, Y: 0}, {X: 1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: 1}}
+
+	var buf bytes.Buffer
+	if err := WriteLife106(&buf, glider); err != nil {
+		t.Fatalf("Unable to write Life 1.06: %s\n", err)
+	}
+
+	expected := "#Life 1.06\n-1 -1\n0 0\n1 0\n-1 1\n0 1\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected Life 1.06:\n%s\nbut got:\n%s\n", expected, buf.String())
+	}
+
+	cells, err := ReadLife106(&buf)
+	if err != nil {
+		t.Fatalf("Unable to read Life 1.06: %s\n", err)
+	}
+	if !sameCells(cells, glider) {
+		t.Fatal("Round trip through Life 1.06 changed the cells")
+	}
+}
+
+func TestReadLife106Error(t *testing.T) {
+	if _, err := ReadLife106(strings.NewReader("#Life 1.06\n1 x\n")); err == nil {
+		t.Error("Unexpectedly read invalid coordinates")
+	}
+}
+
+func TestReadLife105(t *testing.T) {
+	pattern := `#Life 1.05
+#D Two blocks
+#R 23/36
+#P -2 -2
+**
+**
+#P 5 5
+**
+**
+`
+	cells, rules, err := ReadLife105(strings.NewReader(pattern))
+	if err != nil {
+		t.Fatalf("Unable to read Life 1.05: %s\n", err)
+	}
+
+	expected := []life.Location{{X: -2, Y: -2}, {X: -1, Y: -2}, {X: -2, Y: -1}, {X: -1, Y: -1}, {X: 5, Y: 5}, {X: 6, Y: 5}, {X: 5, Y: 6}, {X: 6, Y: 6}}
+	if !sameCells(cells, expected) {
+		t.Fatalf("Read unexpected cells: %v\n", cells)
+	}
+	if rules.String() != "B36/S23" {
+		t.Errorf("Expected HighLife rules but found %s\n", rules.String())
+	}
+}
+
+func TestLife105RoundTrip(t *testing.T) {
+	gun := cellsFromRows(gosperGliderGun)
+	for i := range gun {
+		gun[i].X -= 10
+		gun[i].Y += 3
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLife105(&buf, gun, ConwayRules()); err != nil {
+		t.Fatalf("Unable to write Life 1.05: %s\n", err)
+	}
+
+	cells, rules, err := ReadLife105(&buf)
+	if err != nil {
+		t.Fatalf("Unable to read Life 1.05: %s\n", err)
+	}
+	if !sameCells(cells, gun) {
+		t.Fatal("Round trip through Life 1.05 changed the cells")
+	}
+	conway := ConwayRules()
+	if !rules.Equals(&conway) {
+		t.Errorf("Round trip changed the rules to %s\n", rules.String())
+	}
+}
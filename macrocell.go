@@ -0,0 +1,144 @@
+package biologist
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Level of the 8x8 leaf nodes of a macrocell quadtree
+const macrocellLeafLevel = 3
+
+type macrocellWriter struct {
+	buf   bytes.Buffer
+	nodes map[string]int
+	count int
+}
+
+// node returns the number of the node for the given line, writing the line if it has not been seen yet
+func (t *macrocellWriter) node(line string) int {
+	if num, exists := t.nodes[line]; exists {
+		return num
+	}
+	t.count++
+	t.nodes[line] = t.count
+	t.buf.WriteString(line)
+	t.buf.WriteString("\n")
+	return t.count
+}
+
+// leaf writes an 8x8 bitmap as rows terminated by '$', omitting trailing dead cells and empty rows
+func (t *macrocellWriter) leaf(origin life.Location, cells []life.Location) int {
+	var rows [1 << macrocellLeafLevel][1 << macrocellLeafLevel]bool
+	for _, loc := range cells {
+		rows[loc.Y-origin.Y][loc.X-origin.X] = true
+	}
+
+	var line bytes.Buffer
+	for _, row := range rows {
+		last := -1
+		for x, alive := range row {
+			if alive {
+				last = x
+			}
+		}
+		for x := 0; x <= last; x++ {
+			if row[x] {
+				line.WriteString("*")
+			} else {
+				line.WriteString(".")
+			}
+		}
+		line.WriteString("$")
+	}
+
+	return t.node(strings.TrimRight(line.String(), "$") + "$")
+}
+
+// quadtree writes the node which covers the square with the given origin and level, returning its number
+func (t *macrocellWriter) quadtree(origin life.Location, level int, cells []life.Location) int {
+	if len(cells) == 0 {
+		return 0
+	}
+	if level == macrocellLeafLevel {
+		return t.leaf(origin, cells)
+	}
+
+	half := 1 << uint(level-1)
+	var quadrants [4][]life.Location
+	for _, loc := range cells {
+		quadrant := 0
+		if loc.X >= origin.X+half {
+			quadrant++
+		}
+		if loc.Y >= origin.Y+half {
+			quadrant += 2
+		}
+		quadrants[quadrant] = append(quadrants[quadrant], loc)
+	}
+
+	nw := t.quadtree(origin, level-1, quadrants[0])
+	ne := t.quadtree(life.Location{X: origin.X + half, Y: origin.Y}, level-1, quadrants[1])
+	sw := t.quadtree(life.Location{X: origin.X, Y: origin.Y + half}, level-1, quadrants[2])
+	se := t.quadtree(life.Location{X: origin.X + half, Y: origin.Y + half}, level-1, quadrants[3])
+
+	return t.node(fmt.Sprintf("%d %d %d %d %d", level, nw, ne, sw, se))
+}
+
+// WriteMacrocell writes the given cells in Golly's Macrocell (.mc) format, which stores the pattern as a
+// quadtree where identical nodes are only written once. The top-left corner of the cells is placed at
+// the top-left corner of the root node
+func WriteMacrocell(w io.Writer, cells []life.Location, rules Rules) error {
+	min, max := bounds(cells)
+
+	level := macrocellLeafLevel
+	for (1<<uint(level)) <= max.X-min.X || (1<<uint(level)) <= max.Y-min.Y {
+		level++
+	}
+
+	writer := &macrocellWriter{nodes: make(map[string]int)}
+	writer.buf.WriteString("[M2] (biologist)\n")
+	writer.buf.WriteString(fmt.Sprintf("#R %s\n", rules.String()))
+	writer.quadtree(min, level, cells)
+
+	_, err := w.Write(writer.buf.Bytes())
+	return err
+}
+
+// ExportMacrocells writes each of the analyzed generations in the given range to a numbered Macrocell
+// file in the directory, stopping early if a generation has not been analyzed. Returns the number of
+// generations which were written
+func (t *Biologist) ExportMacrocells(dir string, start int, end int) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+
+	rules := t.Rules()
+	written := 0
+	for generation := start; generation <= end; generation++ {
+		analysis := t.Analysis(generation)
+		if analysis == nil {
+			break
+		}
+
+		file, err := os.Create(filepath.Join(dir, fmt.Sprintf("%06d.mc", generation)))
+		if err != nil {
+			return written, err
+		}
+		err = WriteMacrocell(file, analysis.Living, rules)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, nil
+}
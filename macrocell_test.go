@@ -0,0 +1,78 @@
+package biologist
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestWriteMacrocellLeaf(t *testing.T) {
+	glider := cellsFromRows([]string{".O.", "..O", "OOO"})
+
+	var buf bytes.Buffer
+	if err := WriteMacrocell(&buf, glider, ConwayRules()); err != nil {
+		t.Fatalf("Unable to write macrocell: %s\n", err)
+	}
+
+	expected := "[M2] (biologist)\n#R B3/S23\n.*$..*$***$\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected macrocell:\n%s\nbut got:\n%s\n", expected, buf.String())
+	}
+}
+
+func TestWriteMacrocellSharedNodes(t *testing.T) {
+	// Two identical blocks in separate quadrants should share a leaf
+	cells := []life.Location{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1},
+		{X: 8, Y: 8}, {X: 9, Y: 8}, {X: 8, Y: 9}, {X: 9, Y: 9},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMacrocell(&buf, cells, ConwayRules()); err != nil {
+		t.Fatalf("Unable to write macrocell: %s\n", err)
+	}
+
+	expected := "[M2] (biologist)\n#R B3/S23\n**$**$\n4 1 0 0 1\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected macrocell:\n%s\nbut got:\n%s\n", expected, buf.String())
+	}
+}
+
+func TestExportMacrocells(t *testing.T) {
+	dir, err := ioutil.TempDir("", "biologist")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s\n", err)
+	}
+	defer os.RemoveAll(dir)
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	biologist.Start()
+	<-biologist.Done()
+	biologist.Stop()
+
+	written, err := biologist.ExportMacrocells(dir, 0, 4)
+	if err != nil {
+		t.Fatalf("Unable to export macrocells: %s\n", err)
+	}
+	if written != 5 {
+		t.Fatalf("Expected 5 generations to be written but found %d\n", written)
+	}
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "000004.mc"))
+	if err != nil {
+		t.Fatalf("Unable to read exported generation: %s\n", err)
+	}
+	if !strings.HasPrefix(string(contents), "[M2]") {
+		t.Errorf("Exported generation is not a macrocell: %s\n", contents)
+	}
+}
@@ -2,33 +2,438 @@ package biologist
 
 import (
 	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"gitlab.com/hokiegeek/life"
 )
 
 // Manager keeps track of all Biologist instances
 type Manager struct { // {{{
 	biologists map[string]*Biologist
+	removed    map[string]chan struct{}
+	seeds      map[string]string
+	storage    Storage
+	catalog    *Catalog
+	predictor  Predictor
+	explorers  map[string]*Explorer
+	evolutions map[string]*Evolution
+	mutex      sync.RWMutex
 }
 
 func (t *Manager) stringID(id []byte) string {
 	return fmt.Sprintf("%x", id)
 }
 
-// Biologist returns the instalce of Biologist with the given ID
+// seedKey identifies seeds which are equivalent on boards of the same size and topology evolving under the same rules.
+// The seed is placed on the board first, just as it is when a biologist is created with it
+func (t *Manager) seedKey(dims life.Dimensions, rules Rules, opts Options, seed []life.Location) string {
+	topology := opts.Topology
+	if topology == EngineTopology {
+		// The engine walls the board in, as does the board which stands in for it
+		topology = Bounded
+	}
+	placed := topology.place(dims, seed)
+	return fmt.Sprintf("%s/%s/%s/%s", dims.String(), rules.String(), opts.String(), equivalenceHash(placed, dims, topology))
+}
+
+func (t *Manager) biologistSeedKey(biologist *Biologist) string {
+	return t.seedKey(biologist.Life.Dimensions(), biologist.Rules(), biologist.Options(), biologist.seed)
+}
+
+// unique filters out any biologist whose seed is equivalent to that of a biologist earlier in the list
+func (t *Manager) unique(biologists []*Biologist) []*Biologist {
+	found := make(map[string]bool)
+	unique := make([]*Biologist, 0)
+	for _, biologist := range biologists {
+		key := t.biologistSeedKey(biologist)
+		if !found[key] {
+			found[key] = true
+			unique = append(unique, biologist)
+		}
+	}
+	return unique
+}
+
+// Biologist returns the instalce of Biologist with the given ID, reopening it from storage if needed.
+// The analyses of the best seeds of each evolution can be found for as long as they remain among the best
 func (t *Manager) Biologist(id []byte) *Biologist {
 	// TODO: validate the input
-	return t.biologists[t.stringID(id)]
+	t.mutex.RLock()
+	biologist, exists := t.biologists[t.stringID(id)]
+	if !exists {
+		for _, evolution := range t.evolutions {
+			if biologist = evolution.biologist(id); biologist != nil {
+				exists = true
+				break
+			}
+		}
+	}
+	storage := t.storage
+	t.mutex.RUnlock()
+	if exists || storage == nil {
+		return biologist
+	}
+
+	biologist, err := t.Reopen(id)
+	if err != nil {
+		return nil
+	}
+	return biologist
+}
+
+// SetStorage enables writing each biologist added from now on to the given storage
+func (t *Manager) SetStorage(storage Storage) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.storage = storage
+}
+
+// SetCatalog enables recording a summary of each run tracked from now on once it finishes
+func (t *Manager) SetCatalog(catalog *Catalog) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.catalog = catalog
 }
 
 // Add keeps track of a new Biologist instance
 func (t *Manager) Add(biologist *Biologist) {
 	// TODO: validate the input
-	t.biologists[t.stringID(biologist.ID)] = biologist
+	t.mutex.RLock()
+	storage := t.storage
+	t.mutex.RUnlock()
+
+	// Writing to storage can be slow, so it is done without holding up every other request
+	if storage != nil {
+		if err := biologist.Persist(storage); err != nil {
+			log.Printf("ERROR: Could not store biologist %x: %s\n", biologist.ID, err)
+		}
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.track(biologist)
+}
+
+// Reopen retrieves the biologist with the given ID from storage and keeps track of it
+func (t *Manager) Reopen(id []byte) (*Biologist, error) {
+	t.mutex.RLock()
+	biologist, exists := t.biologists[t.stringID(id)]
+	storage := t.storage
+	t.mutex.RUnlock()
+
+	if exists {
+		return biologist, nil
+	}
+	if storage == nil {
+		return nil, fmt.Errorf("no storage to reopen biologist %x from", id)
+	}
+
+	// Replaying the stored generations can be slow, so it is done without holding up every other request
+	biologist, err := Reopen(storage, id)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// The biologist may have been reopened by another request in the meantime
+	if existing, exists := t.biologists[t.stringID(id)]; exists {
+		return existing, nil
+	}
+	t.track(biologist)
+
+	return biologist, nil
+}
+
+// Snapshot checkpoints every biologist being tracked which was not yet written to storage
+func (t *Manager) Snapshot() error {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.storage == nil {
+		return fmt.Errorf("no storage to write the snapshot to")
+	}
+
+	ids, err := t.storage.IDs()
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]bool)
+	for _, id := range ids {
+		stored[t.stringID(id)] = true
+	}
+
+	for id, biologist := range t.biologists {
+		if !stored[id] {
+			if err := biologist.Persist(t.storage); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Restore reopens every biologist in storage and resumes the analyses which were still running
+func (t *Manager) Restore() error {
+	t.mutex.RLock()
+	storage := t.storage
+	t.mutex.RUnlock()
+
+	if storage == nil {
+		return fmt.Errorf("no storage to restore from")
+	}
+
+	ids, err := storage.IDs()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		biologist, err := t.Reopen(id)
+		if err != nil {
+			log.Printf("ERROR: Could not restore biologist %x: %s\n", id, err)
+			continue
+		}
+		if biologist.isRunning() {
+			biologist.Start()
+		}
+	}
+
+	return nil
+}
+
+// TrainPredictor fits a new model to the runs which have finished and has every biologist use it
+func (t *Manager) TrainPredictor(epochs int, rate float64) error {
+	// Train on the runs finished so far without holding up requests for the duration of the training
+	t.mutex.RLock()
+	finished := make([]*Biologist, 0)
+	for _, biologist := range t.biologists {
+		if !biologist.Active() {
+			finished = append(finished, biologist)
+		}
+	}
+	t.mutex.RUnlock()
+
+	model := NewLogisticRegression()
+	if err := model.Train(NewDataset(finished), epochs, rate); err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.predictor = model
+	for _, biologist := range t.biologists {
+		biologist.SetPredictor(model)
+	}
+
+	return nil
+}
+
+// track expects the manager to be locked for writing
+func (t *Manager) track(biologist *Biologist) {
+	if t.predictor != nil {
+		biologist.SetPredictor(t.predictor)
+	}
+
+	id := t.stringID(biologist.ID)
+	t.biologists[id] = biologist
+	removed := make(chan struct{})
+	t.removed[id] = removed
+
+	key := t.biologistSeedKey(biologist)
+	if _, exists := t.seeds[key]; !exists {
+		t.seeds[key] = t.stringID(biologist.ID)
+	}
+
+	if t.catalog != nil {
+		go func(catalog *Catalog) {
+			select {
+			case <-biologist.Done():
+			case <-removed:
+				return
+			}
+
+			// Hold off removing the run while it is recorded so that a removed run is never left in the catalog
+			t.mutex.RLock()
+			defer t.mutex.RUnlock()
+			if t.biologists[id] != biologist {
+				return
+			}
+			if err := catalog.Record(biologist); err != nil {
+				log.Printf("ERROR: Could not catalog biologist %x: %s\n", biologist.ID, err)
+			}
+		}(t.catalog)
+	}
 }
 
 // Remove deletes the Biologist instance of the given ID
 func (t *Manager) Remove(id []byte) {
 	// TODO: validate the input
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	biologist, exists := t.biologists[t.stringID(id)]
+	if !exists {
+		return
+	}
+
 	delete(t.biologists, t.stringID(id))
+	close(t.removed[t.stringID(id)])
+	delete(t.removed, t.stringID(id))
+
+	if t.storage != nil {
+		if err := t.storage.Remove(id); err != nil {
+			log.Printf("ERROR: Could not remove biologist %x from storage: %s\n", id, err)
+		}
+	}
+
+	if t.catalog != nil {
+		if err := t.catalog.Remove(id); err != nil {
+			log.Printf("ERROR: Could not remove biologist %x from catalog: %s\n", id, err)
+		}
+	}
+
+	// Hand the seed over to any remaining biologist with an equivalent seed
+	key := t.biologistSeedKey(biologist)
+	if t.seeds[key] == t.stringID(id) {
+		delete(t.seeds, key)
+		for otherID, other := range t.biologists {
+			if t.biologistSeedKey(other) == key {
+				t.seeds[key] = otherID
+				break
+			}
+		}
+	}
+}
+
+// AddExplorer keeps track of a rule-space exploration, cataloging its results if a catalog is set
+func (t *Manager) AddExplorer(explorer *Explorer) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.catalog != nil {
+		explorer.SetCatalog(t.catalog)
+	}
+	t.explorers[t.stringID(explorer.ID)] = explorer
+}
+
+// Explorer returns the exploration with the given ID
+func (t *Manager) Explorer(id []byte) *Explorer {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.explorers[t.stringID(id)]
+}
+
+// RemoveExplorer stops the exploration with the given ID and forgets about it
+func (t *Manager) RemoveExplorer(id []byte) {
+	t.mutex.Lock()
+	explorer, exists := t.explorers[t.stringID(id)]
+	delete(t.explorers, t.stringID(id))
+	t.mutex.Unlock()
+
+	if exists {
+		explorer.Stop()
+	}
+}
+
+// AddEvolution keeps track of a seed evolution
+func (t *Manager) AddEvolution(evolution *Evolution) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.evolutions[t.stringID(evolution.ID)] = evolution
+}
+
+// RemoveEvolution stops the seed evolution with the given ID, forgets about it and removes its checkpoint
+func (t *Manager) RemoveEvolution(id []byte) {
+	t.mutex.Lock()
+	evolution, exists := t.evolutions[t.stringID(id)]
+	delete(t.evolutions, t.stringID(id))
+	t.mutex.Unlock()
+
+	if exists {
+		evolution.discard()
+	}
+}
+
+// Evolution returns the seed evolution with the given ID
+func (t *Manager) Evolution(id []byte) *Evolution {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.evolutions[t.stringID(id)]
+}
+
+// Equivalent returns the biologist whose seed is a translation, rotation or reflection of the given seed
+// on a board of the same dimensions and options which is evolving under the same rules. Only the rotations
+// and reflections which map the board onto itself are considered, as the others change how its edges join
+func (t *Manager) Equivalent(dims life.Dimensions, rules Rules, opts Options, seed []life.Location) *Biologist {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if id, exists := t.seeds[t.seedKey(dims, rules, opts, seed)]; exists {
+		return t.biologists[id]
+	}
+	return nil
+}
+
+// LongestLived ranks the seeds of the finished analyses by the number of generations they lived, up to the given count
+func (t *Manager) LongestLived(count int) []Lifespan {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	finished := make([]*Biologist, 0)
+	lifespans := make(map[*Biologist]*Lifespan)
+	for _, biologist := range t.biologists {
+		if lifespan := biologist.Lifespan(); lifespan != nil {
+			finished = append(finished, biologist)
+			lifespans[biologist] = lifespan
+		}
+	}
+
+	sort.SliceStable(finished, func(i, j int) bool {
+		lhs := lifespans[finished[i]]
+		rhs := lifespans[finished[j]]
+		if lhs.Generations == rhs.Generations {
+			return lhs.InitialPopulation < rhs.InitialPopulation
+		}
+		return lhs.Generations > rhs.Generations
+	})
+
+	ranking := make([]Lifespan, 0)
+	for _, biologist := range t.unique(finished) {
+		if count >= 0 && len(ranking) >= count {
+			break
+		}
+		ranking = append(ranking, *lifespans[biologist])
+	}
+
+	return ranking
+}
+
+// ByGrowth orders all of the biologists by the classification of their growth, most confident first
+func (t *Manager) ByGrowth() []*Biologist {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	biologists := make([]*Biologist, 0)
+	for _, biologist := range t.biologists {
+		biologists = append(biologists, biologist)
+	}
+
+	sort.SliceStable(biologists, func(i, j int) bool {
+		lhs := biologists[i].Growth()
+		rhs := biologists[j].Growth()
+		if lhs.Class == rhs.Class {
+			return lhs.Confidence > rhs.Confidence
+		}
+		return lhs.Class < rhs.Class
+	})
+
+	return t.unique(biologists)
 }
 
 // NewManager creates a new instance of the Biologist manager
@@ -36,6 +441,10 @@ func NewManager() *Manager {
 	m := new(Manager)
 
 	m.biologists = make(map[string]*Biologist, 0)
+	m.removed = make(map[string]chan struct{}, 0)
+	m.seeds = make(map[string]string, 0)
+	m.explorers = make(map[string]*Explorer, 0)
+	m.evolutions = make(map[string]*Evolution, 0)
 
 	return m
 } // }}}
@@ -0,0 +1,293 @@
+package biologist
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestManagerLongestLived(t *testing.T) {
+	mgr := NewManager()
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr.Add(biologist)
+
+	if len(mgr.LongestLived(10)) != 0 {
+		t.Fatal("Found lifespans before any analysis finished")
+	}
+
+	biologist.Start()
+	<-biologist.Done()
+	biologist.Stop()
+
+	ranking := mgr.LongestLived(10)
+	if len(ranking) != 1 {
+		t.Fatalf("Expected 1 ranked lifespan but found %d\n", len(ranking))
+	}
+	if ranking[0].Methuselah {
+		t.Error("Blinker was ranked as a methuselah")
+	}
+}
+
+func TestManagerEquivalent(t *testing.T) {
+	mgr := NewManager()
+
+	size := life.Dimensions{Width: 10, Height: 10}
+	biologist, err := New(size, life.Gliders, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr.Add(biologist)
+
+	conway := ConwayRules()
+	reflected := transformOnBoard(biologist.Seed(), size, transformMirrorVertical)
+	if equivalent := mgr.Equivalent(size, conway, Options{}, reflected); equivalent != biologist {
+		t.Fatal("Did not find biologist with an equivalent seed")
+	}
+
+	// Cells beyond the edges of the board are dropped from the seed, just as they are when it is seeded
+	clipped := append(biologist.Seed(), life.Location{X: -1, Y: -1}, life.Location{X: size.Width, Y: 0})
+	if mgr.Equivalent(size, conway, Options{}, clipped) != biologist {
+		t.Error("Did not find biologist with the same seed once it is placed on the board")
+	}
+
+	// Against the walls of the board the same seed evolves differently than away from them
+	moved := make([]life.Location, 0)
+	for _, loc := range biologist.Seed() {
+		moved = append(moved, life.Location{X: loc.X + 1, Y: loc.Y + 1})
+	}
+	if mgr.Equivalent(size, conway, Options{}, moved) != nil {
+		t.Error("Found equivalent seed elsewhere on a bounded board")
+	}
+
+	otherSize := life.Dimensions{Width: 20, Height: 20}
+	if mgr.Equivalent(otherSize, conway, Options{}, reflected) != nil {
+		t.Error("Found equivalent seed on a board of a different size")
+	}
+
+	highLife := Rules{Born: []int{3, 6}, Survive: []int{2, 3}}
+	if mgr.Equivalent(size, highLife, Options{}, reflected) != nil {
+		t.Error("Found equivalent seed evolving under different rules")
+	}
+
+	duplicate, err := New(size, life.Gliders, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr.Add(duplicate)
+
+	if len(mgr.ByGrowth()) != 1 {
+		t.Error("Results included duplicate seeds")
+	}
+
+	mgr.Remove(biologist.ID)
+	if mgr.Equivalent(size, conway, Options{}, reflected) != duplicate {
+		t.Error("Did not find the remaining equivalent seed after the original was removed")
+	}
+
+	mgr.Remove(duplicate.ID)
+	if mgr.Equivalent(size, conway, Options{}, reflected) != nil {
+		t.Error("Found equivalent seed after it was removed")
+	}
+}
+
+func TestManagerRestore(t *testing.T) {
+	storage, dir := tempStorage(t)
+	defer os.RemoveAll(dir)
+
+	size := life.Dimensions{Width: 32, Height: 32}
+	original, err := New(size, life.Gliders, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr := NewManager()
+	mgr.SetStorage(storage)
+	mgr.Add(original)
+
+	// Analyze a few generations before the "restart"
+	updates := make(chan *life.Generation)
+	stop := original.Life.Start(updates)
+	for i := 0; i < 5; i++ {
+		original.analyze(<-updates)
+	}
+	stop()
+	// The analysis was running when the process went away
+	original.setRunning(true)
+
+	stopped, err := New(size, life.Gliders, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr.Add(stopped)
+	stopped.Start()
+	stopped.Stop()
+	stopped.wait()
+
+	restored := NewManager()
+	restored.SetStorage(storage)
+	if err := restored.Restore(); err != nil {
+		t.Fatalf("Unable to restore manager: %s\n", err)
+	}
+	resumed := restored.Biologist(original.ID)
+	if resumed == nil {
+		t.Fatal("Biologist was not restored")
+	}
+	time.Sleep(time.Millisecond * 50)
+	resumed.Stop()
+
+	reference, err := New(size, life.Gliders, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	reference.Start()
+	time.Sleep(time.Millisecond * 50)
+	reference.Stop()
+
+	if resumed.analyses.Count() <= 6 {
+		t.Fatalf("Restored analysis did not resume: only %d generations analyzed\n", resumed.analyses.Count())
+	}
+	if idle := restored.Biologist(stopped.ID); idle == nil || idle.isRunning() || idle.analyses.Count() != stopped.analyses.Count() {
+		t.Error("Restored analysis which had been stopped was resumed")
+	}
+	if resumed.SeedHash() != reference.SeedHash() {
+		t.Error("Restored biologist does not remember its original seed")
+	}
+	for generation := 0; generation < resumed.analyses.Count() && generation < reference.analyses.Count(); generation++ {
+		if !sameCells(resumed.Analysis(generation).Living, reference.Analysis(generation).Living) {
+			t.Fatalf("Resumed generation %d does not match the uninterrupted one\n", generation)
+		}
+	}
+}
+
+func TestManagerConcurrentTraining(t *testing.T) {
+	mgr := NewManager()
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			// Training fails until there are finished runs to learn from
+			mgr.TrainPredictor(1, 0.1)
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		biologist, err := New(size, life.Blinkers, life.ConwayTester())
+		if err != nil {
+			t.Fatalf("Unable to create biologist: %s\n", err)
+		}
+		mgr.Add(biologist)
+		mgr.ByGrowth()
+		mgr.Remove(biologist.ID)
+	}
+	<-done
+}
+
+func TestManagerConcurrentReopen(t *testing.T) {
+	storage, dir := tempStorage(t)
+	defer os.RemoveAll(dir)
+
+	original, err := New(life.Dimensions{Width: 16, Height: 16}, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	if err := original.Persist(storage); err != nil {
+		t.Fatalf("Unable to store biologist: %s\n", err)
+	}
+
+	mgr := NewManager()
+	mgr.SetStorage(storage)
+
+	reopened := make(chan *Biologist)
+	for i := 0; i < 4; i++ {
+		go func() { reopened <- mgr.Biologist(original.ID) }()
+	}
+	first := <-reopened
+	for i := 1; i < 4; i++ {
+		if biologist := <-reopened; biologist == nil || biologist != first {
+			t.Error("Concurrent requests reopened different biologists")
+		}
+	}
+}
+
+// transformOnBoard rotates or reflects the cells along with the board they are on
+func transformOnBoard(cells []life.Location, dims life.Dimensions, transform int) []life.Location {
+	return canonicalOnBoard(cells, dims, []int{transform})
+}
+
+func TestManagerEquivalentTopology(t *testing.T) {
+	rPentomino := cellsFromRows([]string{".OO", "OO.", ".O."})
+
+	tests := []struct {
+		dims     life.Dimensions
+		topology Topology
+		rotates  bool
+	}{
+		{life.Dimensions{Width: 16, Height: 16}, Torus, true},
+		{life.Dimensions{Width: 16, Height: 16}, KleinBottle, false},
+		{life.Dimensions{Width: 16, Height: 16}, CrossSurface, true},
+		{life.Dimensions{Width: 24, Height: 16}, Torus, false},
+		{life.Dimensions{Width: 24, Height: 16}, CrossSurface, false},
+		{life.Dimensions{Width: 24, Height: 16}, Unbounded, true},
+	}
+	for _, test := range tests {
+		rotated := transformOnBoard(rPentomino, test.dims, transformRotate90)
+		reflected := transformOnBoard(rPentomino, test.dims, transformMirrorHorizontal)
+		if test.dims.Width != test.dims.Height {
+			// The rotated board has different dimensions, so only the seed itself is rotated
+			rotated = transformCells(rPentomino, transformRotate90)
+		}
+		translated := make([]life.Location, 0)
+		for _, loc := range rPentomino {
+			translated = append(translated, life.Location{X: loc.X + 5, Y: loc.Y + 3})
+		}
+
+		mgr := NewManager()
+		opts := Options{Topology: test.topology}
+		biologist, err := NewWithOptions(test.dims, Pattern(rPentomino), ConwayRules(), opts)
+		if err != nil {
+			t.Fatalf("Unable to create biologist: %s\n", err)
+		}
+		mgr.Add(biologist)
+
+		if mgr.Equivalent(test.dims, ConwayRules(), opts, reflected) != biologist {
+			t.Errorf("Reflected seed was not equivalent on a %s %s\n", test.dims.String(), test.topology.String())
+		}
+		if equivalent := mgr.Equivalent(test.dims, ConwayRules(), opts, rotated) == biologist; equivalent != test.rotates {
+			t.Errorf("Expected rotated seed to be equivalent on a %s %s to be %t\n", test.dims.String(), test.topology.String(), test.rotates)
+		}
+		if equivalent := mgr.Equivalent(test.dims, ConwayRules(), opts, translated) == biologist; equivalent != test.topology.translatable() {
+			t.Errorf("Expected translated seed to be equivalent on a %s %s to be %t\n", test.dims.String(), test.topology.String(), test.topology.translatable())
+		}
+	}
+}
+
+func TestManagerEvolutionBest(t *testing.T) {
+	mgr := NewManager()
+
+	evolution, err := NewEvolution(testEvolutionOptions())
+	if err != nil {
+		t.Fatalf("Unable to create evolution: %s\n", err)
+	}
+	mgr.AddEvolution(evolution)
+	evolution.Start()
+	<-evolution.Done()
+
+	best := evolution.Best()
+	if len(best) == 0 || best[0].Biologist == nil {
+		t.Fatal("Evolution did not keep the analysis of its best seed")
+	}
+	if mgr.Biologist(best[0].Biologist.ID) != best[0].Biologist {
+		t.Error("Did not find the analysis of the best seed of the evolution")
+	}
+	if len(mgr.ByGrowth()) != 0 {
+		t.Error("Best seeds of the evolution were added to the manager")
+	}
+}
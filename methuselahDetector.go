@@ -0,0 +1,116 @@
+package biologist
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+const (
+	// Seeds which take fewer generations than this to settle down are not considered methuselahs
+	methuselahMinLifespan = 50
+	// Number of generations per initial living cell needed before a seed is considered a methuselah
+	methuselahMinRatio = 10
+)
+
+// Lifespan summarizes how a seed evolved until it stabilized or died
+type Lifespan struct { // {{{
+	ID                []byte
+	Seed              []life.Location
+	InitialPopulation int
+	PeakPopulation    int
+	PeakGeneration    int
+	Generations       int
+	Census            Census
+	Methuselah        bool
+}
+
+func (t *Lifespan) String() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("%x", t.ID))
+	buf.WriteString(fmt.Sprintf("\nInitial Population: %d", t.InitialPopulation))
+	buf.WriteString(fmt.Sprintf("\nPeak Population: %d (generation %d)", t.PeakPopulation, t.PeakGeneration))
+	buf.WriteString(fmt.Sprintf("\nGenerations: %d", t.Generations))
+	buf.WriteString(fmt.Sprintf("\nMethuselah: %t", t.Methuselah))
+	buf.WriteString("\n")
+	buf.WriteString(t.Census.String())
+	return buf.String()
+} // }}}
+
+type methuselahDetector struct { // {{{
+	log               *log.Logger
+	InitialPopulation int
+	PeakPopulation    int
+	PeakGeneration    int
+	Lifespan          int
+	Census            Census
+	takeCensus        func([]life.Location) Census
+	Finished          bool
+	Detected          bool
+}
+
+// analyze tracks the population of the given generation. Once the analysis is no longer active,
+// the lifespan is recorded as either the generation the cycle started at or the generation it died in
+func (m *methuselahDetector) analyze(analysis *Analysis, generation int, cycleStart int) bool {
+	if m.Finished {
+		return m.Detected
+	}
+
+	population := len(analysis.Living)
+	if generation == 0 {
+		m.InitialPopulation = population
+	}
+	if population > m.PeakPopulation {
+		m.PeakPopulation = population
+		m.PeakGeneration = generation
+	}
+
+	switch analysis.Status {
+	case Stable:
+		m.Lifespan = cycleStart
+	case Dead:
+		m.Lifespan = generation
+	default:
+		return false
+	}
+
+	m.Finished = true
+	m.Census = m.takeCensus(analysis.Living)
+	m.Detected = m.Lifespan >= methuselahMinLifespan && m.Lifespan >= m.InitialPopulation*methuselahMinRatio
+	if m.Detected {
+		m.log.Printf("Found methuselah which lived %d generations from %d cells\n", m.Lifespan, m.InitialPopulation)
+	}
+
+	return m.Detected
+}
+
+func (m *methuselahDetector) String() string {
+	var buf bytes.Buffer
+
+	buf.WriteString("Initial: ")
+	buf.WriteString(fmt.Sprintf("%d\n", m.InitialPopulation))
+	buf.WriteString("Peak: ")
+	buf.WriteString(fmt.Sprintf("%d at %d\n", m.PeakPopulation, m.PeakGeneration))
+	buf.WriteString("Lifespan: ")
+	buf.WriteString(fmt.Sprintf("%d\n", m.Lifespan))
+	buf.WriteString("Detected: ")
+	buf.WriteString(fmt.Sprintf("%t\n", m.Detected))
+
+	return buf.String()
+}
+
+func newMethuselahDetector() *methuselahDetector {
+	m := new(methuselahDetector)
+	m.log = log.New(os.Stdout, "[methuselahDetector] ", 0)
+
+	m.takeCensus = takeCensus
+	m.Finished = false
+	m.Detected = false
+
+	return m
+} // }}}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,55 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestMethuselahDetector(t *testing.T) {
+	detector := newMethuselahDetector()
+
+	seed := &Analysis{Status: Active, Living: []life.Location{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}}}
+	if detector.analyze(seed, 0, -1) {
+		t.Fatal("Detected methuselah on the seed generation")
+	}
+
+	peak := &Analysis{Status: Active, Living: make([]life.Location, 300)}
+	for i := range peak.Living {
+		peak.Living[i] = life.Location{X: i, Y: 0}
+	}
+	detector.analyze(peak, 800, -1)
+
+	final := &Analysis{Status: Stable, Living: []life.Location{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}}
+	if !detector.analyze(final, 1110, 1103) {
+		t.Fatalf("Did not detect methuselah:\n%s", detector.String())
+	}
+
+	if detector.InitialPopulation != 5 {
+		t.Errorf("Expected initial population of 5 but found %d\n", detector.InitialPopulation)
+	}
+	if detector.PeakPopulation != 300 || detector.PeakGeneration != 800 {
+		t.Errorf("Expected peak population of 300 at generation 800 but found %d at %d\n", detector.PeakPopulation, detector.PeakGeneration)
+	}
+	if detector.Lifespan != 1103 {
+		t.Errorf("Expected lifespan of 1103 but found %d\n", detector.Lifespan)
+	}
+	if len(detector.Census.Objects) != 1 {
+		t.Errorf("Expected final census with 1 object but found %d\n", len(detector.Census.Objects))
+	}
+}
+
+func TestMethuselahDetectorShortLived(t *testing.T) {
+	detector := newMethuselahDetector()
+
+	seed := &Analysis{Status: Active, Living: []life.Location{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}}
+	detector.analyze(seed, 0, -1)
+
+	final := &Analysis{Status: Dead, Living: []life.Location{}}
+	if detector.analyze(final, 40, -1) {
+		t.Fatal("Unexpectedly detected a methuselah which died quickly")
+	}
+	if !detector.Finished || detector.Lifespan != 40 {
+		t.Fatalf("Expected finished lifespan of 40 but found %d\n", detector.Lifespan)
+	}
+}
@@ -0,0 +1,99 @@
+package biologist
+
+import (
+	"fmt"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Neighborhood determines which of the cells around a cell count as its neighbors
+type Neighborhood int // {{{
+
+const (
+	// Moore counts every cell within the radius, including the diagonals
+	Moore Neighborhood = iota
+	// VonNeumann counts the cells which can be reached within the radius without moving diagonally
+	VonNeumann
+	// Hexagonal emulates a hexagonal grid by leaving out the top-right and bottom-left diagonals
+	Hexagonal
+)
+
+func (t Neighborhood) String() string {
+	switch t {
+	case Moore:
+		return "Moore"
+	case VonNeumann:
+		return "VonNeumann"
+	case Hexagonal:
+		return "Hexagonal"
+	}
+
+	return "Unknown"
+}
+
+// suffix returns the letter which follows the B/S notation of rules using the neighborhood
+func (t Neighborhood) suffix() string {
+	switch t {
+	case VonNeumann:
+		return "V"
+	case Hexagonal:
+		return "H"
+	}
+	return ""
+}
+
+// ltlSuffix returns the letter which follows the N in the Larger than Life notation of rules using the neighborhood
+func (t Neighborhood) ltlSuffix() string {
+	switch t {
+	case VonNeumann:
+		return "N"
+	case Hexagonal:
+		return "H"
+	}
+	return "M"
+}
+
+func parseNeighborhood(suffix string, ltl bool) (Neighborhood, error) {
+	for neighborhood := Moore; neighborhood <= Hexagonal; neighborhood++ {
+		if (ltl && neighborhood.ltlSuffix() == suffix) || (!ltl && neighborhood.suffix() == suffix) {
+			return neighborhood, nil
+		}
+	}
+	return Moore, fmt.Errorf("unknown neighborhood: %s", suffix)
+}
+
+// offsets lists the relative locations of every neighbor within the radius, row by row
+func (t Neighborhood) offsets(radius int) []life.Location {
+	offsets := make([]life.Location, 0)
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			switch t {
+			case VonNeumann:
+				if abs(dx)+abs(dy) > radius {
+					continue
+				}
+			case Hexagonal:
+				if abs(dx-dy) > radius {
+					continue
+				}
+			}
+			offsets = append(offsets, life.Location{X: dx, Y: dy})
+		}
+	}
+	return offsets
+} // }}}
+
+func abs(val int) int {
+	if val < 0 {
+		return -val
+	}
+	return val
+}
+
+// mooreOffsets are the neighbors of a cell in Conway's Game of Life
+var mooreOffsets = Moore.offsets(1)
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,77 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestNeighborhoodOffsets(t *testing.T) {
+	tests := []struct {
+		neighborhood Neighborhood
+		radius       int
+		expected     int
+	}{
+		{Moore, 1, 8},
+		{Moore, 2, 24},
+		{VonNeumann, 1, 4},
+		{VonNeumann, 2, 12},
+		{Hexagonal, 1, 6},
+		{Hexagonal, 2, 18},
+	}
+
+	for _, test := range tests {
+		if offsets := test.neighborhood.offsets(test.radius); len(offsets) != test.expected {
+			t.Errorf("%s neighborhood of radius %d has %d neighbors instead of %d\n", test.neighborhood.String(), test.radius, len(offsets), test.expected)
+		}
+	}
+
+	for _, offset := range Hexagonal.offsets(1) {
+		if (offset.X == 1 && offset.Y == -1) || (offset.X == -1 && offset.Y == 1) {
+			t.Errorf("Hexagonal neighborhood includes the diagonal %s\n", offset.String())
+		}
+	}
+}
+
+func TestCensusNeighborhood(t *testing.T) {
+	diagonal := []life.Location{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}
+
+	if census := takeCensusOn(diagonal, geometry{topology: Bounded, offsets: VonNeumann.offsets(1)}); len(census.Objects) != 3 {
+		t.Errorf("Expected 3 objects in the von Neumann neighborhood but found %d\n", len(census.Objects))
+	}
+	if census := takeCensusOn(diagonal, geometry{topology: Bounded, offsets: mooreOffsets}); len(census.Objects) != 1 {
+		t.Errorf("Expected 1 object in the Moore neighborhood but found %d\n", len(census.Objects))
+	}
+}
+
+func TestNeighborhoodBiologist(t *testing.T) {
+	// Under B1/S1V a lone cell grows into a diamond with the von Neumann neighborhood
+	rules, err := ParseRules("B1/S1V")
+	if err != nil {
+		t.Fatalf("Unable to parse rules: %s\n", err)
+	}
+
+	size := life.Dimensions{Width: 16, Height: 16}
+	seed := []life.Location{{X: 8, Y: 8}}
+	biologist, err := NewWithOptions(size, Pattern(seed), rules, Options{Topology: Bounded})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	if used := biologist.Rules(); !used.Equals(&rules) {
+		t.Fatalf("Biologist is using %s instead of %s\n", used.String(), rules.String())
+	}
+
+	listener := make(chan *life.Generation)
+	stop := biologist.Life.Start(listener)
+	generation := <-listener
+	stop()
+
+	if len(generation.Living) != 4 {
+		t.Fatalf("Expected the 4 orthogonal neighbors to be born but found %d living cells\n", len(generation.Living))
+	}
+	for _, loc := range generation.Living {
+		if abs(loc.X-8)+abs(loc.Y-8) != 1 {
+			t.Errorf("Cell %s is not an orthogonal neighbor of the seed\n", loc.String())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package biologist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// npyAlignment is the size the NumPy header, including the preamble, is padded to
+const npyAlignment = 64
+
+// writeNPYHeader writes a version 1.0 header for a C-ordered array of unsigned bytes with the given shape
+func writeNPYHeader(w io.Writer, shape ...int) error {
+	var dims bytes.Buffer
+	for _, dim := range shape {
+		dims.WriteString(fmt.Sprintf("%d, ", dim))
+	}
+	header := fmt.Sprintf("{'descr': '|u1', 'fortran_order': False, 'shape': (%s), }", bytes.TrimSuffix(dims.Bytes(), []byte(" ")))
+
+	// The magic string, version and header length take 10 bytes and the header ends in a newline
+	padding := npyAlignment - (10+len(header)+1)%npyAlignment
+	header += string(bytes.Repeat([]byte(" "), padding%npyAlignment)) + "\n"
+
+	if _, err := w.Write([]byte("\x93NUMPY\x01\x00")); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, uint16(len(header))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, header)
+	return err
+}
+
+// WriteNPY writes the boards of the given range of generations as a NumPy array of shape
+// (generations, height, width) where living cells are 1 and dead cells are 0. The boards of an unbounded
+// board cover every cell which has lived so far
+func (t *Biologist) WriteNPY(w io.Writer, start int, count int) error {
+	origin, dims := t.extent()
+
+	boards := make([][]byte, 0)
+	for generation := start; generation < start+count; generation++ {
+		analysis := t.Analysis(generation)
+		if analysis == nil {
+			break
+		}
+
+		board := make([]byte, dims.Width*dims.Height)
+		for _, loc := range analysis.Living {
+			x, y := loc.X-origin.X, loc.Y-origin.Y
+			if x >= 0 && y >= 0 && x < dims.Width && y < dims.Height {
+				board[y*dims.Width+x] = 1
+			}
+		}
+		boards = append(boards, board)
+	}
+	if len(boards) == 0 {
+		return errors.New("no generations to write")
+	}
+
+	if err := writeNPYHeader(w, len(boards), dims.Height, dims.Width); err != nil {
+		return err
+	}
+	for _, board := range boards {
+		if _, err := w.Write(board); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// vim: set foldmethod=marker:
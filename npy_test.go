@@ -0,0 +1,49 @@
+package biologist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestWriteNPY(t *testing.T) {
+	size := life.Dimensions{Width: 4, Height: 3}
+	biologist, err := New(size, Pattern([]life.Location{{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}}), life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	biologist.Start()
+	<-biologist.Done()
+
+	var buf bytes.Buffer
+	if err := biologist.WriteNPY(&buf, 0, 5); err != nil {
+		t.Fatalf("Unable to write NumPy array: %s\n", err)
+	}
+	data := buf.Bytes()
+
+	if !bytes.HasPrefix(data, []byte("\x93NUMPY\x01\x00")) {
+		t.Fatal("Missing NumPy magic string")
+	}
+	headerLen := int(binary.LittleEndian.Uint16(data[8:10]))
+	if (10+headerLen)%npyAlignment != 0 {
+		t.Errorf("Header of %d bytes is not aligned\n", headerLen)
+	}
+	header := string(data[10 : 10+headerLen])
+	if !bytes.Contains([]byte(header), []byte("'shape': (5, 3, 4,)")) || header[len(header)-1] != '\n' {
+		t.Errorf("Unexpected header: %q\n", header)
+	}
+
+	boards := data[10+headerLen:]
+	if len(boards) != 5*3*4 {
+		t.Fatalf("Expected %d bytes of boards but found %d\n", 5*3*4, len(boards))
+	}
+	// The first board has a vertical blinker in the second column
+	for y := 0; y < 3; y++ {
+		if boards[y*4+1] != 1 || boards[y*4] != 0 {
+			t.Errorf("Unexpected first board: %v\n", boards[:12])
+			break
+		}
+	}
+}
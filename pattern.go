@@ -0,0 +1,69 @@
+package biologist
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Pattern creates a seed function for New which places the given cells at the offset
+func Pattern(cells []life.Location) func(life.Dimensions, life.Location) []life.Location {
+	return func(dims life.Dimensions, offset life.Location) []life.Location {
+		seed := make([]life.Location, len(cells))
+		for i, loc := range cells {
+			seed[i] = life.Location{X: loc.X + offset.X, Y: loc.Y + offset.Y}
+		}
+		return seed
+	}
+}
+
+// Center translates the cells so that their bounding box sits in the middle of a board of the given dimensions,
+// which brings patterns centered on the origin, as Life 1.05 and 1.06 conventionally are, onto the board
+func Center(cells []life.Location, dims life.Dimensions) []life.Location {
+	if len(cells) == 0 {
+		return cells
+	}
+
+	min, max := bounds(cells)
+	dx := (dims.Width-(max.X-min.X+1))/2 - min.X
+	dy := (dims.Height-(max.Y-min.Y+1))/2 - min.Y
+
+	centered := make([]life.Location, len(cells))
+	for i, loc := range cells {
+		centered[i] = life.Location{X: loc.X + dx, Y: loc.Y + dy}
+	}
+	return centered
+}
+
+// ReadPattern determines which of the supported formats (RLE, plaintext, Life 1.05 or Life 1.06) the
+// pattern is written in and parses it. Formats which do not specify rules use Conway's Game of Life
+func ReadPattern(r io.Reader) ([]life.Location, Rules, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, ConwayRules(), err
+	}
+
+	first := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			first = line
+			break
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(first, life106Header):
+		cells, err := ReadLife106(bytes.NewReader(data))
+		return cells, ConwayRules(), err
+	case strings.HasPrefix(first, life105Header):
+		return ReadLife105(bytes.NewReader(data))
+	case strings.HasPrefix(first, "!") || strings.Trim(first, ".O*") == "":
+		cells, err := ReadCells(bytes.NewReader(data))
+		return cells, ConwayRules(), err
+	}
+
+	return ReadRLE(bytes.NewReader(data))
+}
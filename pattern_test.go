@@ -0,0 +1,61 @@
+package biologist
+
+import (
+	"strings"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestPattern(t *testing.T) {
+	seed := Pattern([]life.Location{{X: 0, Y: 0}, {X: 1, Y: 1}})
+	cells := seed(life.Dimensions{Width: 5, Height: 5}, life.Location{X: 2, Y: 3})
+
+	expected := []life.Location{{X: 2, Y: 3}, {X: 3, Y: 4}}
+	if !sameCells(cells, expected) {
+		t.Fatalf("Pattern was not placed at the offset: %v\n", cells)
+	}
+}
+
+func TestReadPattern(t *testing.T) {
+	gun := cellsFromRows(gosperGliderGun)
+
+	formats := map[string]string{
+		"rle":   gosperGliderGunRLE,
+		"cells": gosperGliderGunCells,
+	}
+	for format, pattern := range formats {
+		cells, _, err := ReadPattern(strings.NewReader(pattern))
+		if err != nil {
+			t.Errorf("Unable to read %s pattern: %s\n", format, err)
+			continue
+		}
+		if !sameCells(cells, gun) {
+			t.Errorf("Read unexpected cells from %s pattern\n", format)
+		}
+	}
+
+	cells, _, err := ReadPattern(strings.NewReader("#Life 1.06\n0 0\n1 1\n"))
+	if err != nil || len(cells) != 2 {
+		t.Errorf("Unable to read Life 1.06 pattern: %v\n", err)
+	}
+
+	cells, rules, err := ReadPattern(strings.NewReader("#Life 1.05\n#R 23/36\n#P 0 0\n***\n"))
+	if err != nil || len(cells) != 3 || rules.String() != "B36/S23" {
+		t.Errorf("Unable to read Life 1.05 pattern: %v\n", err)
+	}
+}
+
+func TestCenter(t *testing.T) {
+	dims := life.Dimensions{Width: 10, Height: 8}
+	cells := []life.Location{{X: -1, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}}
+
+	centered := Center(cells, dims)
+	min, max := bounds(centered)
+	if min.X != 3 || min.Y != 2 || max.X != 5 || max.Y != 4 {
+		t.Errorf("Centered cells span %s - %s\n", min.String(), max.String())
+	}
+	if CanonicalHash(centered) != CanonicalHash(cells) {
+		t.Error("Centering changed the shape of the cells")
+	}
+}
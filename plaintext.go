@@ -0,0 +1,73 @@
+package biologist
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// ReadCells parses a pattern in the plaintext (.cells) format used by LifeWiki
+func ReadCells(r io.Reader) ([]life.Location, error) {
+	cells := make([]life.Location, 0)
+
+	scanner := bufio.NewScanner(r)
+	y := 0
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		for x, c := range line {
+			switch c {
+			case 'O', '*':
+				cells = append(cells, life.Location{X: x, Y: y})
+			case '.':
+			default:
+				return nil, fmt.Errorf("invalid character '%c' in row %d", c, y)
+			}
+		}
+		y++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cells, nil
+}
+
+// WriteCells writes the given cells in the plaintext (.cells) format, with their top-left corner at the origin
+func WriteCells(w io.Writer, cells []life.Location, name string) error {
+	min, max := bounds(cells)
+
+	alive := make(map[life.Location]bool)
+	for _, loc := range cells {
+		alive[loc] = true
+	}
+
+	var buf bytes.Buffer
+	if name != "" {
+		buf.WriteString("!Name: ")
+		buf.WriteString(name)
+		buf.WriteString("\n")
+	}
+	if len(cells) > 0 {
+		for y := min.Y; y <= max.Y; y++ {
+			for x := min.X; x <= max.X; x++ {
+				if alive[life.Location{X: x, Y: y}] {
+					buf.WriteString("O")
+				} else {
+					buf.WriteString(".")
+				}
+			}
+			buf.WriteString("\n")
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,56 @@
+package biologist
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const gosperGliderGunCells = `!Name: Gosper glider gun
+!
+........................O...........
+......................O.O...........
+............OO......OO............OO
+...........O...O....OO............OO
+OO........O.....O...OO..............
+OO........O...O.OO....O.O...........
+..........O.....O.......O...........
+...........O...O....................
+............OO......................
+`
+
+func TestReadCells(t *testing.T) {
+	cells, err := ReadCells(strings.NewReader(gosperGliderGunCells))
+	if err != nil {
+		t.Fatalf("Unable to read cells: %s\n", err)
+	}
+
+	if !sameCells(cells, cellsFromRows(gosperGliderGun)) {
+		t.Fatalf("Read unexpected cells: %v\n", cells)
+	}
+}
+
+func TestReadCellsError(t *testing.T) {
+	if _, err := ReadCells(strings.NewReader("..O\n.x.\n")); err == nil {
+		t.Error("Unexpectedly read cells with an invalid character")
+	}
+}
+
+func TestCellsRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCells(&buf, cellsFromRows(gosperGliderGun), "Gosper glider gun"); err != nil {
+		t.Fatalf("Unable to write cells: %s\n", err)
+	}
+
+	if buf.String() != strings.Replace(gosperGliderGunCells, "!\n", "", 1) {
+		t.Fatalf("Wrote unexpected cells:\n%s\n", buf.String())
+	}
+
+	cells, err := ReadCells(&buf)
+	if err != nil {
+		t.Fatalf("Unable to read cells: %s\n", err)
+	}
+	if !sameCells(cells, cellsFromRows(gosperGliderGun)) {
+		t.Fatal("Round trip through the plaintext format changed the cells")
+	}
+}
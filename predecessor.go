@@ -0,0 +1,321 @@
+package biologist
+
+import (
+	"errors"
+	"fmt"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+type predecessorResult int // {{{
+
+const (
+	// PredecessorUnknown means the search gave up before finding a predecessor or ruling one out
+	PredecessorUnknown predecessorResult = iota
+	// PredecessorFound means a configuration was found which evolves into the target
+	PredecessorFound
+	// GardenOfEden means no configuration within the searched region evolves into the target
+	GardenOfEden
+)
+
+func (t predecessorResult) String() string {
+	switch t {
+	case PredecessorUnknown:
+		return "Unknown"
+	case PredecessorFound:
+		return "Found"
+	case GardenOfEden:
+		return "GardenOfEden"
+	}
+
+	return "Unknown"
+} // }}}
+
+// PredecessorOptions bounds the search for predecessors
+type PredecessorOptions struct {
+	// Margin is how many cells beyond the bounding box of the living cells a predecessor may extend
+	Margin int
+	// MaxNodes is how many cells the search may try to assign before giving up
+	MaxNodes int
+}
+
+// DefaultPredecessorOptions searches within one cell of the living cells and gives up after a million assignments
+func DefaultPredecessorOptions() PredecessorOptions {
+	return PredecessorOptions{Margin: 1, MaxNodes: 1000000}
+}
+
+// Predecessors is the outcome of a search for the configurations leading up to a target
+type Predecessors struct {
+	Result predecessorResult
+	// Generations holds the configurations found, from the furthest back to the parent of the target
+	Generations [][]life.Location
+	Nodes       int
+}
+
+// predecessorSearch looks for parents of a single target by backtracking over the cells of a bounded region
+type predecessorSearch struct { // {{{
+	minX, minY    int
+	width, height int
+	// board is the size of a bounded board, or nil on an unbounded plane
+	board  *life.Dimensions
+	target map[life.Location]bool
+	// allowed[alive][neighbors] is whether a cell becomes alive
+	allowed  [2][maxNeighbors + 1]bool
+	cells    []int
+	nodes    *int
+	maxNodes int
+}
+
+const (
+	cellUnknown = -1
+	cellDead    = 0
+	cellAlive   = 1
+)
+
+// The region of the parent is padded by two cells of dead cells so that every cell it can affect has a full neighborhood
+const predecessorPadding = 2
+
+func (t *predecessorSearch) index(x, y int) int {
+	return (y+predecessorPadding)*(t.width+2*predecessorPadding) + x + predecessorPadding
+}
+
+// feasible returns true if the cell can still evolve into its target state given the cells assigned so far
+func (t *predecessorSearch) feasible(x, y int) bool {
+	// Nothing is ever born beyond the edges of a bounded board
+	if t.board != nil && !t.onBoard(x+t.minX, y+t.minY) {
+		return true
+	}
+
+	alive, unknown := 0, 0
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			switch t.cells[t.index(x+dx, y+dy)] {
+			case cellAlive:
+				alive++
+			case cellUnknown:
+				unknown++
+			}
+		}
+	}
+
+	wanted := t.target[life.Location{X: x + t.minX, Y: y + t.minY}]
+	center := t.cells[t.index(x, y)]
+	for state := cellDead; state <= cellAlive; state++ {
+		if center != cellUnknown && center != state {
+			continue
+		}
+		for neighbors := alive; neighbors <= alive+unknown; neighbors++ {
+			if t.allowed[state][neighbors] == wanted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *predecessorSearch) onBoard(x, y int) bool {
+	return x >= 0 && x < t.board.Width && y >= 0 && y < t.board.Height
+}
+
+// consistent checks every cell whose neighborhood includes the given cell
+func (t *predecessorSearch) consistent(x, y int) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if !t.feasible(x+dx, y+dy) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (t *predecessorSearch) living() []life.Location {
+	living := make([]life.Location, 0)
+	for y := 0; y < t.height; y++ {
+		for x := 0; x < t.width; x++ {
+			if t.cells[t.index(x, y)] == cellAlive {
+				living = append(living, life.Location{X: x + t.minX, Y: y + t.minY})
+			}
+		}
+	}
+	return living
+}
+
+// search assigns the cells of the region in order, calling found with each parent until it returns true.
+// It returns true if found accepted a parent and false once every assignment was tried, or an error if the budget ran out
+func (t *predecessorSearch) search(position int, found func([]life.Location) bool) (bool, error) {
+	if position == t.width*t.height {
+		return found(t.living()), nil
+	}
+
+	x, y := position%t.width, position/t.width
+	cell := t.index(x, y)
+	for state := cellDead; state <= cellAlive; state++ {
+		*t.nodes++
+		if *t.nodes > t.maxNodes {
+			return false, errors.New("search budget exhausted")
+		}
+
+		t.cells[cell] = state
+		if t.consistent(x, y) {
+			done, err := t.search(position+1, found)
+			if done || err != nil {
+				t.cells[cell] = cellUnknown
+				return done, err
+			}
+		}
+	}
+	t.cells[cell] = cellUnknown
+
+	return false, nil
+}
+
+func newPredecessorSearch(target []life.Location, rules Rules, opts PredecessorOptions, board *life.Dimensions, nodes *int) *predecessorSearch {
+	s := new(predecessorSearch)
+
+	min, max := bounds(target)
+	min.X, min.Y = min.X-opts.Margin, min.Y-opts.Margin
+	max.X, max.Y = max.X+opts.Margin, max.Y+opts.Margin
+	if board != nil {
+		// The walls of a bounded board are dead, so the region ends at its edges
+		if min.X < 0 {
+			min.X = 0
+		}
+		if min.Y < 0 {
+			min.Y = 0
+		}
+		if max.X >= board.Width {
+			max.X = board.Width - 1
+		}
+		if max.Y >= board.Height {
+			max.Y = board.Height - 1
+		}
+	}
+	s.minX, s.minY = min.X, min.Y
+	s.width = max.X - min.X + 1
+	s.height = max.Y - min.Y + 1
+	s.board = board
+
+	s.target = make(map[life.Location]bool, len(target))
+	for _, loc := range target {
+		s.target[loc] = true
+	}
+
+	tester := rules.Tester()
+	for neighbors := 0; neighbors <= maxNeighbors; neighbors++ {
+		s.allowed[cellDead][neighbors] = tester(neighbors, false)
+		s.allowed[cellAlive][neighbors] = tester(neighbors, true)
+	}
+
+	// Everything outside of the region is dead
+	s.cells = make([]int, (s.width+2*predecessorPadding)*(s.height+2*predecessorPadding))
+	for y := 0; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			s.cells[s.index(x, y)] = cellUnknown
+		}
+	}
+
+	s.nodes = nodes
+	s.maxNodes = opts.MaxNodes
+
+	return s
+} // }}}
+
+// FindPredecessors searches for a configuration which evolves into the living cells after the given number
+// of generations, with each configuration confined to the margin around the bounding box of the one after it.
+// Cells beyond the region are treated as dead on an unbounded plane, so no search is made under rules where
+// cells are born without neighbors. Only the two states and neighborhood of Conway's Game of Life are supported
+func FindPredecessors(living []life.Location, rules Rules, generations int, opts PredecessorOptions) (Predecessors, error) {
+	return findPredecessors(living, rules, generations, opts, nil)
+}
+
+// findPredecessors confines the search to a bounded board of the given size, or to the unbounded plane if it is nil
+func findPredecessors(living []life.Location, rules Rules, generations int, opts PredecessorOptions, board *life.Dimensions) (Predecessors, error) {
+	var result Predecessors
+	if generations <= 0 {
+		return result, errors.New("the number of generations must be positive")
+	}
+	if rules.Neighborhood != Moore || rules.radius() != 1 {
+		return result, fmt.Errorf("cannot search for predecessors in the %s neighborhood of %s", rules.Neighborhood.String(), rules.String())
+	}
+	if rules.states() > 2 {
+		return result, fmt.Errorf("cannot search for predecessors under the Generations rules %s", rules.String())
+	}
+	for _, num := range rules.Born {
+		if num == 0 {
+			return result, fmt.Errorf("cannot search for predecessors under %s", rules.String())
+		}
+	}
+	if opts.Margin < 0 {
+		return result, errors.New("the margin cannot be negative")
+	}
+
+	chain := make([][]life.Location, generations)
+	var exhausted error
+
+	var level func(target []life.Location, remaining int) (bool, error)
+	level = func(target []life.Location, remaining int) (bool, error) {
+		// Nothing comes from nothing
+		if len(target) == 0 {
+			for i := 0; i < remaining; i++ {
+				chain[i] = []life.Location{}
+			}
+			return true, nil
+		}
+
+		search := newPredecessorSearch(target, rules, opts, board, &result.Nodes)
+		return search.search(0, func(parent []life.Location) bool {
+			chain[remaining-1] = parent
+			if remaining == 1 {
+				return true
+			}
+			done, err := level(parent, remaining-1)
+			if err != nil {
+				exhausted = err
+				return true
+			}
+			return done
+		})
+	}
+
+	found, err := level(living, generations)
+	if err == nil {
+		err = exhausted
+	}
+
+	switch {
+	case err != nil:
+		result.Result = PredecessorUnknown
+	case found:
+		result.Result = PredecessorFound
+		result.Generations = chain
+	default:
+		result.Result = GardenOfEden
+	}
+
+	return result, nil
+}
+
+// Predecessors searches for the configurations leading up to the given generation under the biologist's rules.
+// Predecessors on bounded boards stay within the edges, and boards whose edges wrap around are not supported
+func (t *Biologist) Predecessors(generation int, generations int, opts PredecessorOptions) (Predecessors, error) {
+	analysis := t.Analysis(generation)
+	if analysis == nil {
+		return Predecessors{}, fmt.Errorf("generation %d has not been analyzed", generation)
+	}
+
+	switch topology := t.options.Topology; topology {
+	case Unbounded:
+		return findPredecessors(analysis.Living, t.Rules(), generations, opts, nil)
+	case EngineTopology, Bounded:
+		dims := t.Life.Dimensions()
+		return findPredecessors(analysis.Living, t.Rules(), generations, opts, &dims)
+	default:
+		return Predecessors{}, fmt.Errorf("cannot search for predecessors on a %s", topology.String())
+	}
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,137 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// step evolves the cells by a generation on an unbounded plane
+func step(cells []life.Location, rules Rules) []life.Location {
+	tester := rules.Tester()
+	alive := make(map[life.Location]bool)
+	for _, loc := range cells {
+		alive[loc] = true
+	}
+
+	next := make([]life.Location, 0)
+	if len(cells) == 0 {
+		return next
+	}
+	min, max := bounds(cells)
+	for y := min.Y - 1; y <= max.Y+1; y++ {
+		for x := min.X - 1; x <= max.X+1; x++ {
+			neighbors := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if (dx != 0 || dy != 0) && alive[life.Location{X: x + dx, Y: y + dy}] {
+						neighbors++
+					}
+				}
+			}
+			if tester(neighbors, alive[life.Location{X: x, Y: y}]) {
+				next = append(next, life.Location{X: x, Y: y})
+			}
+		}
+	}
+	return next
+}
+
+func TestFindPredecessors(t *testing.T) {
+	rules := ConwayRules()
+	targets := map[string][]life.Location{
+		"blinker": cellsFromRows([]string{"OOO"}),
+		"block":   cellsFromRows([]string{"OO", "OO"}),
+		"glider":  cellsFromRows([]string{".O.", "..O", "OOO"}),
+	}
+
+	for name, target := range targets {
+		for generations := 1; generations <= 2; generations++ {
+			predecessors, err := FindPredecessors(target, rules, generations, DefaultPredecessorOptions())
+			if err != nil {
+				t.Fatalf("Unable to search for predecessors of %s: %s\n", name, err)
+			}
+			if predecessors.Result != PredecessorFound || len(predecessors.Generations) != generations {
+				t.Errorf("Did not find %d generations of predecessors of %s: %s\n", generations, name, predecessors.Result.String())
+				continue
+			}
+
+			cells := predecessors.Generations[0]
+			for _, expected := range append(predecessors.Generations[1:], target) {
+				cells = step(cells, rules)
+				if !sameCells(cells, expected) {
+					t.Errorf("Predecessors of %s do not evolve into it: %v\n", name, predecessors.Generations)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestFindPredecessorsGardenOfEden(t *testing.T) {
+	// Nothing is born and nothing survives, so only an empty board has a parent
+	rules := Rules{Born: []int{}, Survive: []int{}}
+	predecessors, err := FindPredecessors(cellsFromRows([]string{"O"}), rules, 1, DefaultPredecessorOptions())
+	if err != nil {
+		t.Fatalf("Unable to search for predecessors: %s\n", err)
+	}
+	if predecessors.Result != GardenOfEden {
+		t.Errorf("Expected a Garden of Eden but found %s\n", predecessors.Result.String())
+	}
+
+	predecessors, err = FindPredecessors([]life.Location{}, rules, 3, DefaultPredecessorOptions())
+	if err != nil || predecessors.Result != PredecessorFound {
+		t.Errorf("Empty board should be its own predecessor: %s (%v)\n", predecessors.Result.String(), err)
+	}
+}
+
+func TestFindPredecessorsLimits(t *testing.T) {
+	target := cellsFromRows([]string{"O.O.O", ".O.O.", "O.O.O", ".O.O.", "O.O.O"})
+	opts := PredecessorOptions{Margin: 1, MaxNodes: 10}
+	predecessors, err := FindPredecessors(target, ConwayRules(), 1, opts)
+	if err != nil {
+		t.Fatalf("Unable to search for predecessors: %s\n", err)
+	}
+	if predecessors.Result != PredecessorUnknown || predecessors.Nodes <= opts.MaxNodes {
+		t.Errorf("Search did not give up: %s after %d nodes\n", predecessors.Result.String(), predecessors.Nodes)
+	}
+
+	if _, err := FindPredecessors(target, Rules{Born: []int{0}, Survive: []int{}}, 1, opts); err == nil {
+		t.Error("Searched under rules where cells are born without neighbors")
+	}
+	if _, err := FindPredecessors(target, ConwayRules(), 0, opts); err == nil {
+		t.Error("Searched for zero generations")
+	}
+}
+
+func TestBiologistPredecessorsTopology(t *testing.T) {
+	dims := life.Dimensions{Width: 4, Height: 4}
+	block := cellsFromRows([]string{"OO", "OO"})
+
+	b, err := NewWithOptions(dims, Pattern(block), ConwayRules(), Options{Topology: Bounded})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	predecessors, err := b.Predecessors(0, 1, DefaultPredecessorOptions())
+	if err != nil {
+		t.Fatalf("Unable to search for predecessors: %s\n", err)
+	}
+	if predecessors.Result != PredecessorFound {
+		t.Fatalf("Expected to find a predecessor of a block but found %s\n", predecessors.Result.String())
+	}
+	for _, loc := range predecessors.Generations[0] {
+		if loc.X < 0 || loc.X >= dims.Width || loc.Y < 0 || loc.Y >= dims.Height {
+			t.Errorf("Predecessor has a cell beyond the edge of the board at %d,%d\n", loc.X, loc.Y)
+		}
+	}
+
+	for _, topology := range []Topology{Torus, KleinBottle, CrossSurface} {
+		b, err := NewWithOptions(dims, Pattern(block), ConwayRules(), Options{Topology: topology})
+		if err != nil {
+			t.Fatalf("Unable to create biologist: %s\n", err)
+		}
+		if _, err := b.Predecessors(0, 1, DefaultPredecessorOptions()); err == nil {
+			t.Errorf("Searched for predecessors on a %s\n", topology.String())
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package biologist
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// Number of most recent generations the predictions are based on
+	predictorWindow = 10
+	// Number of values describing the recent generations, including the bias
+	predictorFeatures = 6
+)
+
+type outcome int
+
+const (
+	// Dies applies to a run which is expected to lose all of its living cells
+	Dies outcome = iota
+	// Stabilizes applies to a run which is expected to settle into a cycle
+	Stabilizes
+	// Grows applies to a run which is expected to keep growing or emitting
+	Grows
+	numOutcomes
+)
+
+func (t outcome) String() string {
+	switch t {
+	case Dies:
+		return "Dies"
+	case Stabilizes:
+		return "Stabilizes"
+	case Grows:
+		return "Grows"
+	}
+
+	return "Unknown"
+}
+
+// outcomeOf maps the final status of a run onto the outcome being predicted
+func outcomeOf(status string) outcome {
+	switch status {
+	case Dead.String():
+		return Dies
+	case Stable.String():
+		return Stabilizes
+	}
+	return Grows
+}
+
+// Prediction estimates the probability of each outcome of a run as of the given generation
+type Prediction struct { // {{{
+	Generation int
+	Dead       float64
+	Stable     float64
+	Growing    float64
+}
+
+func newPrediction(generation int, probabilities [numOutcomes]float64) Prediction {
+	return Prediction{
+		Generation: generation,
+		Dead:       probabilities[Dies],
+		Stable:     probabilities[Stabilizes],
+		Growing:    probabilities[Grows],
+	}
+}
+
+// Likely returns the outcome with the highest probability
+func (t *Prediction) Likely() outcome {
+	if t.Dead >= t.Stable && t.Dead >= t.Growing {
+		return Dies
+	} else if t.Stable >= t.Growing {
+		return Stabilizes
+	}
+	return Grows
+}
+
+func (t *Prediction) String() string {
+	return fmt.Sprintf("Generation %d: Dies=%.2f Stabilizes=%.2f Grows=%.2f", t.Generation, t.Dead, t.Stable, t.Growing)
+} // }}}
+
+// Predictor estimates how a run will turn out from the features of its most recent generations, oldest first
+type Predictor interface {
+	Predict(run RunFeatures, recent []GenerationFeatures) Prediction
+}
+
+// predictorInputs reduces the recent generations of a run to the values used by the built-in model
+func predictorInputs(run RunFeatures, recent []GenerationFeatures) [predictorFeatures]float64 {
+	var inputs [predictorFeatures]float64
+	inputs[0] = 1
+	if len(recent) == 0 {
+		return inputs
+	}
+
+	area := run.Width * run.Height
+	first := recent[0]
+	last := recent[len(recent)-1]
+
+	// Density of the latest generation
+	inputs[1] = density(last.Population, area)
+
+	// Relative change in the population over the window
+	if first.Population > 0 {
+		inputs[2] = float64(last.Population-first.Population) / float64(first.Population)
+	} else if last.Population > 0 {
+		inputs[2] = 1
+	}
+
+	// Births and deaths per living cell over the window
+	changes := 0
+	population := 0
+	for _, generation := range recent {
+		changes += generation.Births + generation.Deaths
+		population += generation.Population
+	}
+	if population > 0 {
+		inputs[3] = float64(changes) / float64(population)
+	}
+
+	inputs[4] = last.Entropy
+
+	// How much of the board the living cells span
+	if last.Population > 0 {
+		inputs[5] = density((last.MaxX-last.MinX+1)*(last.MaxY-last.MinY+1), area)
+	}
+
+	return inputs
+}
+
+// LogisticRegression is a multinomial logistic regression over the changes in the recent generations
+type LogisticRegression struct { // {{{
+	Weights [numOutcomes][predictorFeatures]float64
+}
+
+func (t *LogisticRegression) probabilities(inputs [predictorFeatures]float64) [numOutcomes]float64 {
+	var scores [numOutcomes]float64
+	highest := math.Inf(-1)
+	for o := range t.Weights {
+		for i, input := range inputs {
+			scores[o] += t.Weights[o][i] * input
+		}
+		if scores[o] > highest {
+			highest = scores[o]
+		}
+	}
+
+	// Softmax, shifted by the highest score to avoid overflows
+	total := 0.0
+	for o := range scores {
+		scores[o] = math.Exp(scores[o] - highest)
+		total += scores[o]
+	}
+	for o := range scores {
+		scores[o] /= total
+	}
+
+	return scores
+}
+
+// Predict estimates the probability of each outcome
+func (t *LogisticRegression) Predict(run RunFeatures, recent []GenerationFeatures) Prediction {
+	generation := 0
+	if len(recent) > 0 {
+		generation = recent[len(recent)-1].Generation
+	}
+	return newPrediction(generation, t.probabilities(predictorInputs(run, recent)))
+}
+
+// Train fits the weights to every window of generations of the runs in the dataset using batch gradient descent
+func (t *LogisticRegression) Train(dataset *Dataset, epochs int, rate float64) error {
+	runs := make(map[string]RunFeatures)
+	for _, run := range dataset.Runs {
+		runs[run.ID] = run
+	}
+
+	inputs := make([][predictorFeatures]float64, 0)
+	labels := make([]outcome, 0)
+	for i := range dataset.Generations {
+		generation := dataset.Generations[i]
+		run, exists := runs[generation.ID]
+		if !exists {
+			continue
+		}
+
+		start := i - predictorWindow + 1
+		if start < 0 {
+			start = 0
+		}
+		for start < i && dataset.Generations[start].ID != generation.ID {
+			start++
+		}
+
+		inputs = append(inputs, predictorInputs(run, dataset.Generations[start:i+1]))
+		labels = append(labels, outcomeOf(run.Outcome))
+	}
+	if len(inputs) == 0 {
+		return errors.New("no generations to train with")
+	}
+
+	for epoch := 0; epoch < epochs; epoch++ {
+		var gradient [numOutcomes][predictorFeatures]float64
+		for sample := range inputs {
+			probabilities := t.probabilities(inputs[sample])
+			for o := range probabilities {
+				err := probabilities[o]
+				if outcome(o) == labels[sample] {
+					err--
+				}
+				for i, input := range inputs[sample] {
+					gradient[o][i] += err * input
+				}
+			}
+		}
+
+		for o := range t.Weights {
+			for i := range t.Weights[o] {
+				t.Weights[o][i] -= rate * gradient[o][i] / float64(len(inputs))
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewLogisticRegression creates a model with weights which favor dying when the population shrinks,
+// stabilizing when few cells change and growing when the population spreads out
+func NewLogisticRegression() *LogisticRegression {
+	l := new(LogisticRegression)
+
+	l.Weights[Dies] = [predictorFeatures]float64{0, -2, -4, 0, 0, 0}
+	l.Weights[Stabilizes] = [predictorFeatures]float64{1, 0, 0, -4, 0, 0}
+	l.Weights[Grows] = [predictorFeatures]float64{-1, 0, 4, 1, 0, 1}
+
+	return l
+} // }}}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,78 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestLogisticRegressionDefaults(t *testing.T) {
+	model := NewLogisticRegression()
+	run := RunFeatures{Width: 10, Height: 10}
+
+	shrinking := []GenerationFeatures{{Population: 20, Deaths: 10}, {Population: 5, Deaths: 15}}
+	if prediction := model.Predict(run, shrinking); prediction.Likely() != Dies {
+		t.Errorf("Shrinking population should be likely to die: %s\n", prediction.String())
+	}
+
+	still := []GenerationFeatures{{Population: 8}, {Population: 8}}
+	if prediction := model.Predict(run, still); prediction.Likely() != Stabilizes {
+		t.Errorf("Unchanging population should be likely to stabilize: %s\n", prediction.String())
+	}
+
+	growing := []GenerationFeatures{{Population: 5, Births: 5}, {Population: 20, Births: 15, MaxX: 9, MaxY: 9}}
+	prediction := model.Predict(run, growing)
+	if prediction.Likely() != Grows {
+		t.Errorf("Growing population should be likely to grow: %s\n", prediction.String())
+	}
+	if prediction.Generation != 0 {
+		t.Errorf("Predicted generation %d instead of 0\n", prediction.Generation)
+	}
+	if total := prediction.Dead + prediction.Stable + prediction.Growing; total < 0.999 || total > 1.001 {
+		t.Errorf("Probabilities add up to %f\n", total)
+	}
+}
+
+func TestLogisticRegressionTrain(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	blinker, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	blinker.Start()
+	<-blinker.Done()
+
+	dataset := NewDataset([]*Biologist{blinker})
+
+	// Start from weights which mistake the blinker for something which grows
+	model := new(LogisticRegression)
+	model.Weights[Grows][0] = 5
+	if err := model.Train(dataset, 500, 0.5); err != nil {
+		t.Fatalf("Unable to train: %s\n", err)
+	}
+
+	prediction := model.Predict(dataset.Runs[0], dataset.Generations)
+	if prediction.Likely() != Stabilizes {
+		t.Errorf("Trained model did not predict the blinker stabilizing: %s\n", prediction.String())
+	}
+
+	if err := model.Train(new(Dataset), 1, 0.5); err == nil {
+		t.Error("Training without any generations did not fail")
+	}
+}
+
+func TestBiologistPrediction(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	prediction := biologist.Prediction(0)
+	if prediction == nil || prediction.Generation != 0 {
+		t.Fatalf("Unexpected prediction: %v\n", prediction)
+	}
+	if biologist.Prediction(1) != nil {
+		t.Error("Predicted from a generation which was not analyzed yet")
+	}
+}
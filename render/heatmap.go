@@ -0,0 +1,60 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// blend mixes the two colors, where a weight of 0 is entirely the first color and 1 is entirely the second
+func blend(from color.Color, to color.Color, weight float64) color.Color {
+	fr, fg, fb, _ := from.RGBA()
+	tr, tg, tb, _ := to.RGBA()
+	mix := func(f, t uint32) uint8 {
+		return uint8((float64(f>>8) * (1 - weight)) + (float64(t>>8) * weight))
+	}
+	return color.RGBA{mix(fr, tr), mix(fg, tg), mix(fb, tb), 0xff}
+}
+
+// Heatmap writes a grid of counts, indexed by row and then column, as an image where each cell is
+// shaded from the background color to the living color relative to the highest count
+func Heatmap(w io.Writer, grid [][]int, opts Options) error {
+	height := len(grid)
+	width := 0
+	max := 0
+	for _, row := range grid {
+		if len(row) > width {
+			width = len(row)
+		}
+		for _, count := range row {
+			if count > max {
+				max = count
+			}
+		}
+	}
+
+	if err := opts.Validate(life.Dimensions{Width: width, Height: height}); err != nil {
+		return err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width*opts.CellSize, height*opts.CellSize))
+	for y, row := range grid {
+		for x := 0; x < width; x++ {
+			weight := 0.0
+			if x < len(row) && max > 0 {
+				weight = float64(row[x]) / float64(max)
+			}
+			c := blend(opts.Background, opts.Alive, weight)
+			for py := y * opts.CellSize; py < (y+1)*opts.CellSize; py++ {
+				for px := x * opts.CellSize; px < (x+1)*opts.CellSize; px++ {
+					img.Set(px, py, c)
+				}
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
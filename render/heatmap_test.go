@@ -0,0 +1,35 @@
+package render
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestHeatmap(t *testing.T) {
+	grid := [][]int{
+		{0, 1, 0},
+		{0, 4, 0},
+	}
+	opts := DefaultOptions()
+
+	var buf bytes.Buffer
+	if err := Heatmap(&buf, grid, opts); err != nil {
+		t.Fatalf("Unable to render heatmap: %s\n", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Unable to decode rendered heatmap: %s\n", err)
+	}
+	if img.Bounds().Dx() != 3*opts.CellSize || img.Bounds().Dy() != 2*opts.CellSize {
+		t.Fatalf("Heatmap has unexpected size %s\n", img.Bounds().String())
+	}
+
+	hottest, _, _, _ := img.At(opts.CellSize, opts.CellSize).RGBA()
+	warm, _, _, _ := img.At(opts.CellSize, 0).RGBA()
+	cold, _, _, _ := img.At(0, 0).RGBA()
+	if !(hottest < warm && warm < cold) {
+		t.Errorf("Expected cells to darken with activity: %d, %d, %d\n", cold, warm, hottest)
+	}
+}
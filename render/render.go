@@ -0,0 +1,295 @@
+// Package render draws the analyses of a biologist as images
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"time"
+
+	"gitlab.com/hokiegeek/biologist"
+	"gitlab.com/hokiegeek/life"
+)
+
+const (
+	// MaxFrames is the most analyses which are drawn into a single animation
+	MaxFrames = 1000
+	// MaxPixels is the largest area of a single image
+	MaxPixels = 4096 * 4096
+	// MaxAnimationPixels is the largest area of all of the frames of an animation together, as they are
+	// all drawn before the animation is encoded
+	MaxAnimationPixels = 16 * MaxPixels
+	// MaxDelay is the longest delay between frames which the APNG frame control can hold
+	MaxDelay = 65535 * time.Millisecond
+)
+
+// Options controls how the cells of each analysis are drawn
+type Options struct { // {{{
+	CellSize   int
+	Background color.Color
+	Alive      color.Color
+	Born       color.Color
+	Died       color.Color
+	Outline    color.Color
+	Delay      time.Duration
+	// Origin is the cell drawn in the top-left corner, which only differs from 0,0 on unbounded boards
+	Origin life.Location
+}
+
+// DefaultOptions returns options which draw 4 pixel cells on a white background at 10 frames per second
+func DefaultOptions() Options {
+	return Options{
+		CellSize:   4,
+		Background: color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Alive:      color.RGBA{0x00, 0x00, 0x00, 0xff},
+		Born:       color.RGBA{0x2e, 0x8b, 0x57, 0xff},
+		Died:       color.RGBA{0xdc, 0xdc, 0xdc, 0xff},
+		Outline:    color.RGBA{0x1e, 0x90, 0xff, 0xff},
+		Delay:      time.Millisecond * 100,
+	}
+}
+
+// Validate checks that the images of a board of the given dimensions are not too large to draw and that
+// the delay between frames can be written in the animation formats
+func (t *Options) Validate(dims life.Dimensions) error {
+	if t.CellSize <= 0 || t.CellSize > MaxPixels {
+		return fmt.Errorf("invalid cell size %d", t.CellSize)
+	}
+	if pixels := int64(dims.Width) * int64(dims.Height) * int64(t.CellSize) * int64(t.CellSize); pixels > MaxPixels {
+		return fmt.Errorf("images of %d pixels exceed the maximum of %d", pixels, MaxPixels)
+	}
+	if t.Delay <= 0 || t.Delay > MaxDelay {
+		return fmt.Errorf("delay must be between 1 and %d milliseconds", MaxDelay/time.Millisecond)
+	}
+	return nil
+}
+
+func (t *Options) palette() color.Palette {
+	return color.Palette{t.Background, t.Alive, t.Born, t.Died, t.Outline}
+} // }}}
+
+const (
+	backgroundIndex = iota
+	aliveIndex
+	bornIndex
+	diedIndex
+	outlineIndex
+)
+
+// pixel returns the top-left corner of the cell in the image
+func (t *Options) pixel(loc life.Location) (int, int) {
+	return (loc.X - t.Origin.X) * t.CellSize, (loc.Y - t.Origin.Y) * t.CellSize
+}
+
+func fill(img *image.Paletted, loc life.Location, opts Options, index uint8) {
+	left, top := opts.pixel(loc)
+	for y := top; y < top+opts.CellSize; y++ {
+		for x := left; x < left+opts.CellSize; x++ {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
+
+// frame draws the living cells of the analysis, highlighting the cells which were just born or which just died
+func frame(dims life.Dimensions, analysis *biologist.Analysis, opts Options) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, dims.Width*opts.CellSize, dims.Height*opts.CellSize), opts.palette())
+
+	for _, loc := range analysis.Living {
+		fill(img, loc, opts, aliveIndex)
+	}
+	for _, change := range analysis.Changes {
+		switch change.Change {
+		case biologist.Born:
+			fill(img, change.Location, opts, bornIndex)
+		case biologist.Died:
+			fill(img, change.Location, opts, diedIndex)
+		}
+	}
+
+	return img
+}
+
+// Analyses collects up to the given number of consecutive analyses, stopping at the first generation which has not been analyzed
+func Analyses(b *biologist.Biologist, start int, count int) []*biologist.Analysis {
+	analyses := make([]*biologist.Analysis, 0)
+	for generation := start; generation < start+count; generation++ {
+		analysis := b.Analysis(generation)
+		if analysis == nil {
+			break
+		}
+		analyses = append(analyses, analysis)
+	}
+	return analyses
+}
+
+// Frame returns the dimensions of the images of the biologist's board and moves the origin of the options to
+// its top-left corner, as the board grows along with the cells which spread across it if it is unbounded
+func Frame(b *biologist.Biologist, opts *Options) life.Dimensions {
+	min, max := b.Bounds()
+	opts.Origin = min
+	return life.Dimensions{Width: max.X - min.X + 1, Height: max.Y - min.Y + 1}
+}
+
+// ValidateAnimation checks the options as Validate does and also that the given number of frames of a board
+// of the given dimensions are not too many to draw
+func (t *Options) ValidateAnimation(dims life.Dimensions, frames int) error {
+	if frames <= 0 || frames > MaxFrames {
+		return fmt.Errorf("the number of frames must be between 1 and %d", MaxFrames)
+	}
+	if err := t.Validate(dims); err != nil {
+		return err
+	}
+	if pixels := int64(frames) * int64(dims.Width) * int64(dims.Height) * int64(t.CellSize) * int64(t.CellSize); pixels > MaxAnimationPixels {
+		return fmt.Errorf("animations of %d pixels exceed the maximum of %d", pixels, MaxAnimationPixels)
+	}
+	return nil
+}
+
+func validateAnimation(dims life.Dimensions, analyses []*biologist.Analysis, opts Options) error {
+	if len(analyses) == 0 {
+		return errors.New("no analyses to render")
+	}
+	return opts.ValidateAnimation(dims, len(analyses))
+}
+
+// GIF writes the analyses as the frames of an animated GIF which loops forever
+func GIF(w io.Writer, dims life.Dimensions, analyses []*biologist.Analysis, opts Options) error {
+	if err := validateAnimation(dims, analyses, opts); err != nil {
+		return err
+	}
+
+	// GIF delays are in hundredths of a second
+	delay := int(opts.Delay / (time.Millisecond * 10))
+
+	anim := &gif.GIF{LoopCount: 0}
+	for _, analysis := range analyses {
+		anim.Image = append(anim.Image, frame(dims, analysis, opts))
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	return gif.EncodeAll(w, anim)
+}
+
+type pngChunk struct {
+	name string
+	data []byte
+}
+
+func readPNGChunks(data []byte) ([]pngChunk, error) {
+	const signatureLength = 8
+	if len(data) < signatureLength {
+		return nil, errors.New("truncated PNG")
+	}
+
+	chunks := make([]pngChunk, 0)
+	for pos := signatureLength; pos < len(data); {
+		if pos+8 > len(data) {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		name := string(data[pos+4 : pos+8])
+		if pos+12+length > len(data) {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{name: name, data: data[pos+8 : pos+8+length]})
+		pos += 12 + length
+	}
+	return chunks, nil
+}
+
+func writePNGChunk(buf *bytes.Buffer, name string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], name)
+	buf.Write(header[:])
+	buf.Write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	buf.Write(sum[:])
+}
+
+// APNG writes the analyses as the frames of an animated PNG which loops forever. Each frame is encoded
+// by the standard PNG encoder and its image data is then repackaged into the APNG frame chunks
+func APNG(w io.Writer, dims life.Dimensions, analyses []*biologist.Analysis, opts Options) error {
+	if err := validateAnimation(dims, analyses, opts); err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	out.WriteString("\x89PNG\r\n\x1a\n")
+
+	sequence := uint32(0)
+	for i, analysis := range analyses {
+		var encoded bytes.Buffer
+		img := frame(dims, analysis, opts)
+		if err := png.Encode(&encoded, img); err != nil {
+			return err
+		}
+		chunks, err := readPNGChunks(encoded.Bytes())
+		if err != nil {
+			return err
+		}
+
+		// The header and palette of the first frame are shared by all of the frames
+		if i == 0 {
+			for _, chunk := range chunks {
+				switch chunk.name {
+				case "IHDR":
+					writePNGChunk(&out, chunk.name, chunk.data)
+
+					var actl [8]byte
+					binary.BigEndian.PutUint32(actl[:4], uint32(len(analyses)))
+					binary.BigEndian.PutUint32(actl[4:], 0)
+					writePNGChunk(&out, "acTL", actl[:])
+				case "PLTE", "tRNS":
+					writePNGChunk(&out, chunk.name, chunk.data)
+				}
+			}
+		}
+
+		var fctl [26]byte
+		binary.BigEndian.PutUint32(fctl[0:], sequence)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(img.Bounds().Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(img.Bounds().Dy()))
+		binary.BigEndian.PutUint32(fctl[12:], 0)
+		binary.BigEndian.PutUint32(fctl[16:], 0)
+		binary.BigEndian.PutUint16(fctl[20:], uint16(opts.Delay/time.Millisecond))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		fctl[24] = 0 // dispose: none
+		fctl[25] = 0 // blend: source
+		writePNGChunk(&out, "fcTL", fctl[:])
+		sequence++
+
+		for _, chunk := range chunks {
+			if chunk.name != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				writePNGChunk(&out, "IDAT", chunk.data)
+			} else {
+				fdat := make([]byte, 4+len(chunk.data))
+				binary.BigEndian.PutUint32(fdat, sequence)
+				copy(fdat[4:], chunk.data)
+				writePNGChunk(&out, "fdAT", fdat)
+				sequence++
+			}
+		}
+	}
+	writePNGChunk(&out, "IEND", nil)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,157 @@
+package render
+
+import (
+	"bytes"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	"gitlab.com/hokiegeek/biologist"
+	"gitlab.com/hokiegeek/life"
+)
+
+func blinkerAnalyses(t *testing.T, count int) (life.Dimensions, []*biologist.Analysis) {
+	size := life.Dimensions{Width: 3, Height: 3}
+	b, err := biologist.New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	b.Start()
+	<-b.Done()
+	b.Stop()
+
+	analyses := Analyses(b, 0, count)
+	if len(analyses) != count {
+		t.Fatalf("Expected %d analyses but found %d\n", count, len(analyses))
+	}
+	return size, analyses
+}
+
+func TestFrame(t *testing.T) {
+	size, analyses := blinkerAnalyses(t, 2)
+	opts := DefaultOptions()
+
+	img := frame(size, analyses[1], opts)
+	if img.Bounds().Dx() != size.Width*opts.CellSize || img.Bounds().Dy() != size.Height*opts.CellSize {
+		t.Fatalf("Frame has unexpected size %s\n", img.Bounds().String())
+	}
+
+	for _, change := range analyses[1].Changes {
+		expected := uint8(bornIndex)
+		if change.Change == biologist.Died {
+			expected = diedIndex
+		}
+		x := change.X * opts.CellSize
+		y := change.Y * opts.CellSize
+		if index := img.ColorIndexAt(x, y); index != expected {
+			t.Errorf("Expected color %d for %s but found %d\n", expected, change.String(), index)
+		}
+	}
+}
+
+func TestGIF(t *testing.T) {
+	size, analyses := blinkerAnalyses(t, 4)
+
+	var buf bytes.Buffer
+	if err := GIF(&buf, size, analyses, DefaultOptions()); err != nil {
+		t.Fatalf("Unable to render GIF: %s\n", err)
+	}
+
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("Unable to decode rendered GIF: %s\n", err)
+	}
+	if len(anim.Image) != len(analyses) {
+		t.Fatalf("Expected %d frames but found %d\n", len(analyses), len(anim.Image))
+	}
+	if anim.Delay[0] != 10 {
+		t.Errorf("Expected delay of 10 but found %d\n", anim.Delay[0])
+	}
+}
+
+func TestAPNG(t *testing.T) {
+	size, analyses := blinkerAnalyses(t, 4)
+
+	var buf bytes.Buffer
+	if err := APNG(&buf, size, analyses, DefaultOptions()); err != nil {
+		t.Fatalf("Unable to render APNG: %s\n", err)
+	}
+
+	chunks, err := readPNGChunks(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Unable to read rendered APNG: %s\n", err)
+	}
+	counts := make(map[string]int)
+	for _, chunk := range chunks {
+		counts[chunk.name]++
+	}
+	if counts["acTL"] != 1 || counts["fcTL"] != len(analyses) || counts["fdAT"] < len(analyses)-1 {
+		t.Fatalf("Unexpected chunks in APNG: %v\n", counts)
+	}
+
+	// Decoders which do not support animation still see the first frame
+	if _, err := png.Decode(&buf); err != nil {
+		t.Fatalf("Unable to decode rendered APNG as a PNG: %s\n", err)
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GIF(&buf, life.Dimensions{Width: 1, Height: 1}, nil, DefaultOptions()); err == nil {
+		t.Error("Unexpectedly rendered GIF without any analyses")
+	}
+	if err := APNG(&buf, life.Dimensions{Width: 1, Height: 1}, nil, DefaultOptions()); err == nil {
+		t.Error("Unexpectedly rendered APNG without any analyses")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	size := life.Dimensions{Width: 32, Height: 32}
+	if opts := DefaultOptions(); opts.Validate(size) != nil {
+		t.Fatalf("Default options are invalid: %s\n", opts.Validate(size))
+	}
+
+	invalid := map[string]func(*Options){
+		"zero cell size":  func(opts *Options) { opts.CellSize = 0 },
+		"huge cell size":  func(opts *Options) { opts.CellSize = 1 << 20 },
+		"zero delay":      func(opts *Options) { opts.Delay = 0 },
+		"excessive delay": func(opts *Options) { opts.Delay = MaxDelay + time.Millisecond },
+	}
+	for name, modify := range invalid {
+		opts := DefaultOptions()
+		modify(&opts)
+		if opts.Validate(size) == nil {
+			t.Errorf("Unexpectedly validated options with %s\n", name)
+		}
+	}
+
+	_, analyses := blinkerAnalyses(t, 2)
+	frames := make([]*biologist.Analysis, MaxFrames+1)
+	for i := range frames {
+		frames[i] = analyses[i%len(analyses)]
+	}
+	var buf bytes.Buffer
+	if err := GIF(&buf, life.Dimensions{Width: 3, Height: 3}, frames, DefaultOptions()); err == nil {
+		t.Error("Unexpectedly rendered GIF with too many frames")
+	}
+
+	// Each frame fits, but not all of them together
+	large := DefaultOptions()
+	large.CellSize = 1
+	if err := large.ValidateAnimation(life.Dimensions{Width: 4096, Height: 4096}, MaxFrames); err == nil {
+		t.Error("Unexpectedly validated an animation of too many pixels")
+	}
+}
+
+func TestFrameOrigin(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Origin = life.Location{X: -2, Y: -1}
+	analysis := &biologist.Analysis{Living: []life.Location{{X: -2, Y: -1}}}
+
+	img := frame(life.Dimensions{Width: 3, Height: 3}, analysis, opts)
+	if index := img.ColorIndexAt(0, 0); index != aliveIndex {
+		t.Errorf("Expected the cell at the origin in the top-left corner but found color %d\n", index)
+	}
+}
@@ -0,0 +1,129 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"gitlab.com/hokiegeek/biologist"
+	"gitlab.com/hokiegeek/life"
+)
+
+// outline draws a box just inside the edges of the cells covered by the object
+func outline(img *image.Paletted, object biologist.Object, opts Options) {
+	left, top := opts.pixel(object.Min)
+	right, bottom := opts.pixel(life.Location{X: object.Max.X + 1, Y: object.Max.Y + 1})
+	right, bottom = right-1, bottom-1
+
+	for x := left; x <= right; x++ {
+		img.SetColorIndex(x, top, outlineIndex)
+		img.SetColorIndex(x, bottom, outlineIndex)
+	}
+	for y := top; y <= bottom; y++ {
+		img.SetColorIndex(left, y, outlineIndex)
+		img.SetColorIndex(right, y, outlineIndex)
+	}
+}
+
+// PNG writes the analysis as a single image. If a census is given, then the bounds of each of its
+// objects are outlined. Labels are only included in SVG snapshots
+func PNG(w io.Writer, dims life.Dimensions, analysis *biologist.Analysis, census *biologist.Census, opts Options) error {
+	if err := opts.Validate(dims); err != nil {
+		return err
+	}
+
+	img := frame(dims, analysis, opts)
+	if census != nil {
+		for _, object := range census.Objects {
+			outline(img, object, opts)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func hexColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+func svgRect(buf *bytes.Buffer, loc life.Location, opts Options, fill string) {
+	x, y := opts.pixel(loc)
+	buf.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, x, y, opts.CellSize, opts.CellSize, fill))
+	buf.WriteString("\n")
+}
+
+// labelBaseline places a label just above the object's outline, or below it when there is no room above.
+// Objects spanning the whole image are labeled inside their outline
+func labelBaseline(top, bottom, height, fontSize int) int {
+	if top-1 >= fontSize {
+		return top - 1
+	}
+	if bottom+fontSize <= height {
+		return bottom + fontSize
+	}
+	return fontSize
+}
+
+// SVG writes the analysis as a single vector image. If a census is given, then the bounds of each of
+// its objects are outlined and labeled with the name of the object or its number of cells
+func SVG(w io.Writer, dims life.Dimensions, analysis *biologist.Analysis, census *biologist.Census, opts Options) error {
+	if err := opts.Validate(dims); err != nil {
+		return err
+	}
+
+	width := dims.Width * opts.CellSize
+	height := dims.Height * opts.CellSize
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height, width, height))
+	buf.WriteString("\n")
+	buf.WriteString(fmt.Sprintf(`<rect width="%d" height="%d" fill="%s"/>`, width, height, hexColor(opts.Background)))
+	buf.WriteString("\n")
+
+	changes := make(map[life.Location]string)
+	for _, change := range analysis.Changes {
+		switch change.Change {
+		case biologist.Born:
+			changes[change.Location] = hexColor(opts.Born)
+		case biologist.Died:
+			changes[change.Location] = hexColor(opts.Died)
+		}
+	}
+	for _, loc := range analysis.Living {
+		if _, changed := changes[loc]; !changed {
+			svgRect(&buf, loc, opts, hexColor(opts.Alive))
+		}
+	}
+	for _, change := range analysis.Changes {
+		svgRect(&buf, change.Location, opts, changes[change.Location])
+	}
+
+	if census != nil {
+		outlineColor := hexColor(opts.Outline)
+		for _, object := range census.Objects {
+			x, y := opts.pixel(object.Min)
+			buf.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s"/>`,
+				x, y, (object.Max.X-object.Min.X+1)*opts.CellSize, (object.Max.Y-object.Min.Y+1)*opts.CellSize, outlineColor))
+			buf.WriteString("\n")
+
+			label := object.Label
+			if label == "" {
+				label = fmt.Sprintf("%d cells", len(object.Cells))
+			}
+			fontSize := opts.CellSize * 2
+			buf.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="%d" fill="%s">`,
+				x, labelBaseline(y, y+(object.Max.Y-object.Min.Y+1)*opts.CellSize, height, fontSize), fontSize, outlineColor))
+			xml.EscapeText(&buf, []byte(label))
+			buf.WriteString("</text>\n")
+		}
+	}
+	buf.WriteString("</svg>\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+
+	"gitlab.com/hokiegeek/biologist"
+	"gitlab.com/hokiegeek/life"
+)
+
+func blockAnalysis() *biologist.Analysis {
+	return &biologist.Analysis{
+		Status: biologist.Stable,
+		Living: []life.Location{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}},
+	}
+}
+
+func TestPNG(t *testing.T) {
+	size := life.Dimensions{Width: 4, Height: 4}
+	analysis := blockAnalysis()
+	census := analysis.Census()
+	opts := DefaultOptions()
+
+	var buf bytes.Buffer
+	if err := PNG(&buf, size, analysis, &census, opts); err != nil {
+		t.Fatalf("Unable to render PNG: %s\n", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Unable to decode rendered PNG: %s\n", err)
+	}
+
+	if img.Bounds().Dx() != size.Width*opts.CellSize {
+		t.Fatalf("Snapshot has unexpected width %d\n", img.Bounds().Dx())
+	}
+
+	r, g, b, _ := img.At(opts.CellSize, opts.CellSize).RGBA()
+	er, eg, eb, _ := opts.Outline.RGBA()
+	if r != er || g != eg || b != eb {
+		t.Error("Object was not outlined")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	size := life.Dimensions{Width: 4, Height: 4}
+	analysis := blockAnalysis()
+	census := analysis.Census()
+
+	var buf bytes.Buffer
+	if err := SVG(&buf, size, analysis, &census, DefaultOptions()); err != nil {
+		t.Fatalf("Unable to render SVG: %s\n", err)
+	}
+
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Fatalf("Rendered invalid SVG: %s\n", svg)
+	}
+	if strings.Count(svg, `fill="#000000"`) != len(analysis.Living) {
+		t.Errorf("Expected %d living cells in SVG: %s\n", len(analysis.Living), svg)
+	}
+	if !strings.Contains(svg, ">block</text>") {
+		t.Errorf("Expected block to be labeled: %s\n", svg)
+	}
+}
+
+func TestSVGLabelInside(t *testing.T) {
+	size := life.Dimensions{Width: 4, Height: 8}
+	analysis := &biologist.Analysis{
+		Status: biologist.Stable,
+		Living: []life.Location{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}},
+	}
+	census := analysis.Census()
+	opts := DefaultOptions()
+
+	var buf bytes.Buffer
+	if err := SVG(&buf, size, analysis, &census, opts); err != nil {
+		t.Fatalf("Unable to render SVG: %s\n", err)
+	}
+
+	svg := buf.String()
+	start := strings.Index(svg, "<text")
+	if start < 0 {
+		t.Fatalf("Expected block to be labeled: %s\n", svg)
+	}
+	var x, y, fontSize int
+	if _, err := fmt.Sscanf(svg[start:], `<text x="%d" y="%d" font-size="%d"`, &x, &y, &fontSize); err != nil {
+		t.Fatalf("Unable to read label position: %s\n", err)
+	}
+	if y-fontSize < 0 || y > size.Height*opts.CellSize {
+		t.Errorf("Label at %d with size %d is outside of the image\n", y, fontSize)
+	}
+}
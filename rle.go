@@ -0,0 +1,183 @@
+package biologist
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Longest line which will be written in RLE output
+const rleLineLength = 70
+
+// Most living cells which will be read from a single pattern
+const maxPatternCells = 1 << 20
+
+var rleHeader = regexp.MustCompile(`^\s*x\s*=\s*(\d+)\s*,\s*y\s*=\s*(\d+)\s*(?:,\s*rule\s*=\s*(\S+))?`)
+
+// ReadRLE parses a pattern in the Run Length Encoded format used by Golly and LifeWiki. If the
+// pattern does not specify any rules, then the rules of Conway's Game of Life are assumed
+func ReadRLE(r io.Reader) ([]life.Location, Rules, error) {
+	cells := make([]life.Location, 0)
+	rules := ConwayRules()
+
+	scanner := bufio.NewScanner(r)
+	foundHeader := false
+	width, height := 0, 0
+	x, y := 0, 0
+	count := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !foundHeader {
+			matches := rleHeader.FindStringSubmatch(line)
+			if matches == nil {
+				return nil, rules, fmt.Errorf("invalid RLE header: %s", line)
+			}
+			var errWidth, errHeight error
+			width, errWidth = strconv.Atoi(matches[1])
+			height, errHeight = strconv.Atoi(matches[2])
+			if errWidth != nil || errHeight != nil {
+				return nil, rules, fmt.Errorf("invalid RLE dimensions: %s", line)
+			}
+			if matches[3] != "" {
+				var err error
+				if rules, err = ParseRules(matches[3]); err != nil {
+					return nil, rules, err
+				}
+			}
+			foundHeader = true
+			continue
+		}
+
+		for _, c := range line {
+			switch {
+			case c >= '0' && c <= '9':
+				count += string(c)
+				continue
+			case c == ' ' || c == '\t':
+				continue
+			}
+
+			run := 1
+			if count != "" {
+				var err error
+				if run, err = strconv.Atoi(count); err != nil || run <= 0 {
+					return nil, rules, fmt.Errorf("invalid RLE run count: %s", count)
+				}
+				count = ""
+			}
+
+			switch c {
+			case 'b', '.':
+				x += run
+			case '$':
+				x = 0
+				y += run
+			case '!':
+				return cells, rules, nil
+			default:
+				// Any other state is considered alive
+				if x+run > width || y >= height {
+					return nil, rules, fmt.Errorf("RLE runs beyond the %dx%d pattern", width, height)
+				}
+				if len(cells)+run > maxPatternCells {
+					return nil, rules, fmt.Errorf("RLE pattern has more than %d living cells", maxPatternCells)
+				}
+				for i := 0; i < run; i++ {
+					cells = append(cells, life.Location{X: x + i, Y: y})
+				}
+				x += run
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, rules, err
+	}
+	if !foundHeader {
+		return nil, rules, fmt.Errorf("missing RLE header")
+	}
+
+	return cells, rules, nil
+}
+
+// WriteRLE writes the given cells in the Run Length Encoded format, with their top-left corner at the origin
+func WriteRLE(w io.Writer, cells []life.Location, rules Rules) error {
+	min, max := bounds(cells)
+	width, height := 0, 0
+	if len(cells) > 0 {
+		width = max.X - min.X + 1
+		height = max.Y - min.Y + 1
+	}
+
+	rows := make(map[int]map[int]bool)
+	for _, loc := range cells {
+		if rows[loc.Y-min.Y] == nil {
+			rows[loc.Y-min.Y] = make(map[int]bool)
+		}
+		rows[loc.Y-min.Y][loc.X-min.X] = true
+	}
+
+	// Collect all of the runs, merging the line breaks across any empty rows
+	tokens := make([]string, 0)
+	addRun := func(run int, tag string) {
+		if run > 1 {
+			tokens = append(tokens, strconv.Itoa(run)+tag)
+		} else if run == 1 {
+			tokens = append(tokens, tag)
+		}
+	}
+	newlines := 0
+	for y := 0; y < height; y++ {
+		row := rows[y]
+		if len(row) == 0 {
+			newlines++
+			continue
+		}
+		addRun(newlines, "$")
+		newlines = 1
+
+		run, alive := 0, false
+		for x := 0; x < width; x++ {
+			if row[x] != alive && run > 0 {
+				if alive {
+					addRun(run, "o")
+				} else {
+					addRun(run, "b")
+				}
+				run = 0
+			}
+			alive = row[x]
+			run++
+		}
+		// Trailing dead cells are implied
+		if alive {
+			addRun(run, "o")
+		}
+	}
+	tokens = append(tokens, "!")
+
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("x = %d, y = %d, rule = %s\n", width, height, rules.String()))
+	lineLength := 0
+	for _, token := range tokens {
+		if lineLength+len(token) > rleLineLength {
+			buf.WriteString("\n")
+			lineLength = 0
+		}
+		buf.WriteString(token)
+		lineLength += len(token)
+	}
+	buf.WriteString("\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
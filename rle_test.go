@@ -0,0 +1,100 @@
+package biologist
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+const gosperGliderGunRLE = `#N Gosper glider gun
+#C The first known gun
+x = 36, y = 9, rule = B3/S23
+24bo$22bobo$12b2o6b2o12b2o$11bo3bo4b2o12b2o$2o8bo5bo3b2o$2o8bo3bob2o4b
+obo$10bo5bo7bo$11bo3bo$12b2o!
+`
+
+func sameCells(lhs, rhs []life.Location) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	found := make(map[life.Location]bool)
+	for _, loc := range lhs {
+		found[loc] = true
+	}
+	for _, loc := range rhs {
+		if !found[loc] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestReadRLE(t *testing.T) {
+	cells, rules, err := ReadRLE(strings.NewReader(gosperGliderGunRLE))
+	if err != nil {
+		t.Fatalf("Unable to read RLE: %s\n", err)
+	}
+
+	if !sameCells(cells, cellsFromRows(gosperGliderGun)) {
+		t.Fatalf("Read unexpected cells: %v\n", cells)
+	}
+
+	conway := ConwayRules()
+	if !rules.Equals(&conway) {
+		t.Errorf("Expected Conway rules but found %s\n", rules.String())
+	}
+}
+
+func TestReadRLERules(t *testing.T) {
+	_, rules, err := ReadRLE(strings.NewReader("x = 3, y = 1, rule = 23/36\n3o!"))
+	if err != nil {
+		t.Fatalf("Unable to read RLE: %s\n", err)
+	}
+	if rules.String() != "B36/S23" {
+		t.Errorf("Expected HighLife rules but found %s\n", rules.String())
+	}
+}
+
+func TestReadRLEError(t *testing.T) {
+	if _, _, err := ReadRLE(strings.NewReader("3o!")); err == nil {
+		t.Error("Unexpectedly read RLE without a header")
+	}
+	for _, rle := range []string{"x = 3, y = 1\n999999999o!", "x = 3, y = 1\n99999999999999999999o!", "x = 3, y = 1\n0o!", "x = 3, y = 1\n$o!",
+		"x = 2000000, y = 1\n2000000o!"} {
+		if _, _, err := ReadRLE(strings.NewReader(rle)); err == nil {
+			t.Errorf("Unexpectedly read RLE with invalid runs: %q\n", rle)
+		}
+	}
+}
+
+func TestWriteRLE(t *testing.T) {
+	var buf bytes.Buffer
+	rPentomino := []life.Location{{X: 11, Y: 10}, {X: 12, Y: 10}, {X: 10, Y: 11}, {X: 11, Y: 11}, {X: 11, Y: 12}}
+	if err := WriteRLE(&buf, rPentomino, ConwayRules()); err != nil {
+		t.Fatalf("Unable to write RLE: %s\n", err)
+	}
+
+	expected := "x = 3, y = 3, rule = B3/S23\nb2o$2o$bo!\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected RLE:\n%s\nbut got:\n%s\n", expected, buf.String())
+	}
+}
+
+func TestRLERoundTrip(t *testing.T) {
+	gun := cellsFromRows(gosperGliderGun)
+
+	var buf bytes.Buffer
+	if err := WriteRLE(&buf, gun, ConwayRules()); err != nil {
+		t.Fatalf("Unable to write RLE: %s\n", err)
+	}
+
+	cells, _, err := ReadRLE(&buf)
+	if err != nil {
+		t.Fatalf("Unable to read RLE: %s\n", err)
+	}
+	if !sameCells(cells, gun) {
+		t.Fatal("Round trip through RLE changed the cells")
+	}
+}
@@ -0,0 +1,375 @@
+package biologist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Most neighbors a cell can have in the Moore neighborhood
+const maxNeighbors = 8
+
+// Most states a cell can have under Generations rules
+const maxStates = 256
+
+// Largest radius of a neighborhood. Golly allows up to 500, but every cell counts its neighbors each
+// generation, so the neighborhoods are kept small enough to evolve in reasonable time
+const maxRadius = 10
+
+// Rules describes how many neighbors it takes for a cell to be born or to survive
+type Rules struct { // {{{
+	Born         []int
+	Survive      []int
+	Neighborhood Neighborhood
+	// Radius of the neighborhood, where zero is the same as the single ring of Conway's Game of Life
+	Radius int
+	// States each cell can be in, where anything beyond alive and dead are the refractory states
+	// a dying cell passes through in Generations rules. Zero is the same as 2
+	States int
+}
+
+func (t *Rules) states() int {
+	if t.States < 2 {
+		return 2
+	}
+	return t.States
+}
+
+func (t *Rules) radius() int {
+	if t.Radius < 1 {
+		return 1
+	}
+	return t.Radius
+}
+
+// offsets lists the relative locations of the neighbors of a cell
+func (t *Rules) offsets() []life.Location {
+	return t.Neighborhood.offsets(t.radius())
+}
+
+// engineSupported returns true if the life engine can evolve cells under the rules
+func (t *Rules) engineSupported() bool {
+	return t.states() == 2 && t.radius() == 1 && (t.Neighborhood == Moore || t.Neighborhood == VonNeumann)
+}
+
+// Tester creates a function which determines if a cell with the given number of neighbors will be alive in the next generation
+func (t *Rules) Tester() func(int, bool) bool {
+	maxCount := len(t.offsets())
+	born := make([]bool, maxCount+1)
+	survive := make([]bool, maxCount+1)
+	for _, num := range t.Born {
+		if num >= 0 && num <= maxCount {
+			born[num] = true
+		}
+	}
+	for _, num := range t.Survive {
+		if num >= 0 && num <= maxCount {
+			survive[num] = true
+		}
+	}
+
+	return func(numNeighbors int, isAlive bool) bool {
+		if numNeighbors < 0 || numNeighbors > maxCount {
+			return false
+		}
+		if isAlive {
+			return survive[numNeighbors]
+		}
+		return born[numNeighbors]
+	}
+}
+
+// Equals returns true if both rules have the same conditions
+func (t *Rules) Equals(rhs *Rules) bool {
+	return t.String() == rhs.String()
+}
+
+func (t *Rules) String() string {
+	if t.radius() > 1 {
+		return t.largerThanLife()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("B")
+	for _, num := range t.Born {
+		buf.WriteString(strconv.Itoa(num))
+	}
+	buf.WriteString("/S")
+	for _, num := range t.Survive {
+		buf.WriteString(strconv.Itoa(num))
+	}
+	if t.states() > 2 {
+		buf.WriteString("/")
+		buf.WriteString(strconv.Itoa(t.states()))
+	}
+	buf.WriteString(t.Neighborhood.suffix())
+	return buf.String()
+}
+
+// largerThanLife returns the rules in the notation used for neighborhoods with a radius beyond 1 (R2,C0,M0,S3..5,B4..4,NM)
+func (t *Rules) largerThanLife() string {
+	var buf bytes.Buffer
+	states := 0
+	if t.states() > 2 {
+		states = t.states()
+	}
+	buf.WriteString(fmt.Sprintf("R%d,C%d,M0,", t.radius(), states))
+	buf.WriteString("S")
+	buf.WriteString(formatCountRanges(t.Survive))
+	buf.WriteString(",B")
+	buf.WriteString(formatCountRanges(t.Born))
+	buf.WriteString(",N")
+	buf.WriteString(t.Neighborhood.ltlSuffix())
+	return buf.String()
+}
+
+// survivalBirth returns the rules in the S/B notation (23/3) used by older formats
+func (t *Rules) survivalBirth() string {
+	var buf bytes.Buffer
+	for _, num := range t.Survive {
+		buf.WriteString(strconv.Itoa(num))
+	}
+	buf.WriteString("/")
+	for _, num := range t.Born {
+		buf.WriteString(strconv.Itoa(num))
+	}
+	return buf.String()
+} // }}}
+
+// ConwayRules returns the B3/S23 rules of Conway's Game of Life
+func ConwayRules() Rules {
+	return Rules{Born: []int{3}, Survive: []int{2, 3}}
+}
+
+// formatCountRanges writes the sorted counts as comma-separated ranges of consecutive counts (3..5,7..7)
+func formatCountRanges(counts []int) string {
+	ranges := make([]string, 0)
+	for i := 0; i < len(counts); {
+		j := i
+		for j+1 < len(counts) && counts[j+1] == counts[j]+1 {
+			j++
+		}
+		ranges = append(ranges, fmt.Sprintf("%d..%d", counts[i], counts[j]))
+		i = j + 1
+	}
+	return strings.Join(ranges, ",")
+}
+
+// uniqueCounts sorts the counts and drops any duplicates
+func uniqueCounts(counts []int) []int {
+	found := make(map[int]bool)
+	nums := make([]int, 0)
+	for _, num := range counts {
+		if !found[num] {
+			found[num] = true
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+func parseNeighborCounts(counts string, maxCount int) ([]int, error) {
+	nums := make([]int, 0)
+	for _, c := range counts {
+		num := int(c - '0')
+		if num < 0 || num > 9 || num > maxCount {
+			return nil, fmt.Errorf("invalid neighbor count '%c'", c)
+		}
+		nums = append(nums, num)
+	}
+	return uniqueCounts(nums), nil
+}
+
+// parseCountRange reads either a single count (3) or an inclusive range of counts (3..5)
+func parseCountRange(counts string) ([]int, error) {
+	if counts == "" {
+		return []int{}, nil
+	}
+
+	bounds := strings.SplitN(counts, "..", 2)
+	first, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid neighbor count '%s'", counts)
+	}
+	last := first
+	if len(bounds) == 2 {
+		if last, err = strconv.Atoi(bounds[1]); err != nil {
+			return nil, fmt.Errorf("invalid neighbor count '%s'", counts)
+		}
+	}
+	if first < 0 || last < first {
+		return nil, fmt.Errorf("invalid neighbor count range '%s'", counts)
+	}
+	// No neighborhood has more cells than the Moore neighborhood of the largest radius
+	if last >= (2*maxRadius+1)*(2*maxRadius+1) {
+		return nil, fmt.Errorf("neighbor count range '%s' is too large", counts)
+	}
+
+	nums := make([]int, 0, last-first+1)
+	for num := first; num <= last; num++ {
+		nums = append(nums, num)
+	}
+	return nums, nil
+}
+
+// parseStates reads the number of states of Generations rules, where 0 and 2 both mean alive and dead only
+func parseStates(states string) (int, error) {
+	num, err := strconv.Atoi(states)
+	if err != nil || num < 0 || num == 1 || num > maxStates {
+		return 0, fmt.Errorf("invalid number of states '%s'", states)
+	}
+	if num <= 2 {
+		return 0, nil
+	}
+	return num, nil
+}
+
+// parseLargerThanLife reads rules in the notation used for neighborhoods of any radius (R2,C0,M0,S3..5,B4..4,NM)
+func parseLargerThanLife(rule string) (Rules, error) {
+	rules := Rules{Born: make([]int, 0), Survive: make([]int, 0)}
+
+	radius := 1
+	middle := false
+	var counts *[]int
+	for _, field := range strings.Split(rule, ",") {
+		var err error
+		switch {
+		case strings.HasPrefix(field, "R"):
+			if radius, err = strconv.Atoi(field[1:]); err != nil || radius < 1 {
+				return rules, fmt.Errorf("invalid radius '%s'", field)
+			}
+			if radius > maxRadius {
+				return rules, fmt.Errorf("radius %d exceeds the maximum of %d", radius, maxRadius)
+			}
+		case strings.HasPrefix(field, "C"):
+			if rules.States, err = parseStates(field[1:]); err != nil {
+				return rules, err
+			}
+		case strings.HasPrefix(field, "M"):
+			if field != "M0" && field != "M1" {
+				return rules, fmt.Errorf("invalid middle cell '%s'", field)
+			}
+			middle = field == "M1"
+		case strings.HasPrefix(field, "N"):
+			if rules.Neighborhood, err = parseNeighborhood(field[1:], true); err != nil {
+				return rules, err
+			}
+		case strings.HasPrefix(field, "S"), strings.HasPrefix(field, "B"):
+			if field[0] == 'S' {
+				counts = &rules.Survive
+			} else {
+				counts = &rules.Born
+			}
+			field = field[1:]
+			fallthrough
+		default:
+			// Any other ranges carry on the list of counts before them
+			if counts == nil {
+				return rules, fmt.Errorf("unexpected field '%s'", field)
+			}
+			nums, err := parseCountRange(field)
+			if err != nil {
+				return rules, err
+			}
+			*counts = append(*counts, nums...)
+		}
+	}
+
+	if middle {
+		// Surviving cells counted themselves among their neighbors
+		survive := make([]int, 0, len(rules.Survive))
+		for _, num := range rules.Survive {
+			if num > 0 {
+				survive = append(survive, num-1)
+			}
+		}
+		rules.Survive = survive
+	}
+
+	if radius > 1 {
+		rules.Radius = radius
+	}
+
+	maxCount := len(rules.offsets())
+	rules.Born = uniqueCounts(rules.Born)
+	rules.Survive = uniqueCounts(rules.Survive)
+	for _, counts := range [][]int{rules.Born, rules.Survive} {
+		if len(counts) > 0 && counts[len(counts)-1] > maxCount {
+			return rules, fmt.Errorf("neighbor count %d exceeds the %d neighbors of the neighborhood", counts[len(counts)-1], maxCount)
+		}
+	}
+
+	return rules, nil
+}
+
+// ParseRules reads rules in either the B/S notation (B3/S23) or the S/B notation (23/3), optionally followed by
+// the number of states of Generations rules (B2/S/3) and by V for the von Neumann or H for the hexagonal
+// neighborhood, or in the Larger than Life notation (R2,C0,M0,S3..5,B4..4,NM)
+func ParseRules(rule string) (Rules, error) {
+	var rules Rules
+
+	rule = strings.ToUpper(strings.TrimSpace(rule))
+	if strings.HasPrefix(rule, "R") && strings.Contains(rule, ",") {
+		return parseLargerThanLife(rule)
+	}
+
+	if strings.HasSuffix(rule, "V") || strings.HasSuffix(rule, "H") {
+		rules.Neighborhood, _ = parseNeighborhood(rule[len(rule)-1:], false)
+		rule = rule[:len(rule)-1]
+	}
+	maxCount := len(rules.offsets())
+
+	parts := strings.Split(rule, "/")
+	if len(parts) == 3 {
+		var err error
+		if rules.States, err = parseStates(parts[2]); err != nil {
+			return rules, err
+		}
+		parts = parts[:2]
+	}
+	if len(parts) != 2 {
+		return rules, errors.New("rules must have two or three parts separated by '/'")
+	}
+
+	var born, survive string
+	switch {
+	case strings.HasPrefix(parts[0], "B") && strings.HasPrefix(parts[1], "S"):
+		born, survive = parts[0][1:], parts[1][1:]
+	case strings.HasPrefix(parts[0], "S") && strings.HasPrefix(parts[1], "B"):
+		survive, born = parts[0][1:], parts[1][1:]
+	default:
+		survive, born = parts[0], parts[1]
+	}
+
+	var err error
+	if rules.Born, err = parseNeighborCounts(born, maxCount); err != nil {
+		return rules, err
+	}
+	if rules.Survive, err = parseNeighborCounts(survive, maxCount); err != nil {
+		return rules, err
+	}
+
+	return rules, nil
+}
+
+// rulesOf determines the rules implemented by the given tester by asking it about every neighbor count
+func rulesOf(rulesTester func(int, bool) bool) Rules {
+	rules := Rules{Born: make([]int, 0), Survive: make([]int, 0)}
+	for num := 0; num <= maxNeighbors; num++ {
+		if rulesTester(num, false) {
+			rules.Born = append(rules.Born, num)
+		}
+		if rulesTester(num, true) {
+			rules.Survive = append(rules.Survive, num)
+		}
+	}
+	return rules
+}
+
+// vim: set foldmethod=marker:
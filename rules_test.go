@@ -0,0 +1,76 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestParseRules(t *testing.T) {
+	rules := map[string]string{
+		"B3/S23":                       "B3/S23",
+		"b36/s23":                      "B36/S23",
+		"S23/B3":                       "B3/S23",
+		"23/3":                         "B3/S23",
+		"/2":                           "B2/S",
+		"B2/S34H":                      "B2/S34H",
+		"b3/s12v":                      "B3/S12V",
+		"R2,C0,M0,S5..7,9..9,B6..7,NM": "R2,C0,M0,S5..7,9..9,B6..7,NM",
+		"R5,C0,M1,S34..58,B34..45,NM":  "R5,C0,M0,S33..57,B34..45,NM",
+		"R1,C0,M0,S2..3,B3,NM":         "B3/S23",
+		"R3,C2,M0,S2..8,B3..3,NN":      "R3,C0,M0,S2..8,B3..3,NN",
+	}
+
+	for rule, expected := range rules {
+		parsed, err := ParseRules(rule)
+		if err != nil {
+			t.Errorf("Unable to parse rules %s: %s\n", rule, err)
+			continue
+		}
+		if parsed.String() != expected {
+			t.Errorf("Parsed %s as %s instead of %s\n", rule, parsed.String(), expected)
+		}
+	}
+}
+
+func TestParseRulesError(t *testing.T) {
+	for _, rule := range []string{"", "B3", "B39/S23", "B3/S2x", "B3/S7H", "B5/S2V", "R2,C0,M0,S25,B3,NM", "R2,C1,M0,S2,B3,NM", "B2/S/1", "B2/S/x", "B2/S/3/4", "R2,C0,M0,S2,B3,NX", "R0,C0,M0,S2,B3,NM", "R100000,C0,M0,S2,B3,NM", "R11,C0,M0,S2,B3,NM", "R2,C0,M0,S0..999999999,B3,NM"} {
+		if _, err := ParseRules(rule); err == nil {
+			t.Errorf("Unexpectedly parsed invalid rules '%s'\n", rule)
+		}
+	}
+}
+
+func TestRulesTester(t *testing.T) {
+	rules := ConwayRules()
+	tester := rules.Tester()
+	conway := life.ConwayTester()
+
+	for num := 0; num <= maxNeighbors; num++ {
+		for _, alive := range []bool{true, false} {
+			if tester(num, alive) != conway(num, alive) {
+				t.Errorf("Tester disagrees with Conway for %d neighbors (alive: %t)\n", num, alive)
+			}
+		}
+	}
+}
+
+func TestRulesOf(t *testing.T) {
+	rules := rulesOf(life.ConwayTester())
+	conway := ConwayRules()
+	if !rules.Equals(&conway) {
+		t.Fatalf("Determined rules to be %s instead of %s\n", rules.String(), conway.String())
+	}
+}
+
+func TestRulesTesterNeighborhood(t *testing.T) {
+	rules, err := ParseRules("R2,C0,M0,S10..12,B20..24,NM")
+	if err != nil {
+		t.Fatalf("Unable to parse rules: %s\n", err)
+	}
+	tester := rules.Tester()
+
+	if !tester(24, false) || !tester(11, true) || tester(11, false) || tester(25, false) {
+		t.Error("Tester did not count up to the 24 neighbors of the radius 2 Moore neighborhood")
+	}
+}
@@ -36,6 +36,35 @@ func checksum(cells []life.Location) []byte {
 	return h.Sum(nil)
 }
 
+// stateChecksum covers the dying cells along with the living ones, so that a generation only repeats
+// another when every cell is in the same state
+func stateChecksum(analysis *Analysis) []byte {
+	living := checksum(analysis.Living)
+	if len(analysis.Dying) == 0 {
+		return living
+	}
+
+	dying := make([]CellState, len(analysis.Dying))
+	copy(dying, analysis.Dying)
+	sort.Slice(dying, func(i, j int) bool {
+		if dying[i].Y == dying[j].Y {
+			return dying[i].X < dying[j].X
+		}
+		return dying[i].Y < dying[j].Y
+	})
+
+	var str bytes.Buffer
+	str.Write(living)
+	for _, cell := range dying {
+		str.WriteString(cell.String())
+		str.WriteString(";")
+	}
+
+	h := sha1.New()
+	h.Write(str.Bytes())
+	return h.Sum(nil)
+}
+
 type stabilityDetector struct { // {{{
 	log               *log.Logger
 	analysesChecksums map[string]int
@@ -46,7 +75,7 @@ type stabilityDetector struct { // {{{
 
 func (s *stabilityDetector) analyze(analysis *Analysis, generation int) bool {
 
-	checksum := checksum(analysis.Living)
+	checksum := stateChecksum(analysis)
 	checksumStr := hex.EncodeToString(checksum)
 
 	if gen, exists := s.analysesChecksums[checksumStr]; exists {
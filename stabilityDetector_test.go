@@ -1,9 +1,29 @@
 package biologist
 
 import (
+	"bytes"
 	"testing"
+
+	"gitlab.com/hokiegeek/life"
 )
 
 func TestChecksum(t *testing.T) {
 	t.Skip("TODO")
 }
+
+func TestStateChecksum(t *testing.T) {
+	living := []life.Location{{X: 1, Y: 1}}
+	alive := Analysis{Living: living}
+	dying := Analysis{Living: living, Dying: []CellState{{Location: life.Location{X: 2, Y: 1}, State: 2}}}
+	older := Analysis{Living: living, Dying: []CellState{{Location: life.Location{X: 2, Y: 1}, State: 3}}}
+
+	if !bytes.Equal(stateChecksum(&alive), checksum(living)) {
+		t.Error("Checksum of only living cells changed")
+	}
+	if bytes.Equal(stateChecksum(&alive), stateChecksum(&dying)) {
+		t.Error("Checksum does not cover the dying cells")
+	}
+	if bytes.Equal(stateChecksum(&dying), stateChecksum(&older)) {
+		t.Error("Checksum does not cover the states of the dying cells")
+	}
+}
@@ -0,0 +1,220 @@
+package biologist
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// RunRecord holds what is needed to recreate a biologist
+type RunRecord struct {
+	ID      []byte
+	Dims    life.Dimensions
+	Rules   Rules
+	Options Options
+	Seed    []life.Location
+	// Running is whether the analysis was running, so that only those which were are resumed
+	Running bool
+}
+
+// Storage keeps the analyses of each biologist so that they outlive the process
+type Storage interface {
+	// Create records a new biologist
+	Create(record RunRecord) error
+	// Update replaces the record of the biologist, such as when it is started or stopped
+	Update(record RunRecord) error
+	// Append records the analysis of a generation of the biologist with the given ID
+	Append(id []byte, generation int, analysis Analysis) error
+	// Open retrieves the record of the biologist and each of its analyses in order of generation
+	Open(id []byte) (*RunRecord, []Analysis, error)
+	// Remove deletes everything recorded about the biologist
+	Remove(id []byte) error
+	// IDs lists every biologist which has been recorded
+	IDs() ([][]byte, error)
+}
+
+// fileRecord is a single line of the log of a biologist. The first line holds the run and every other line holds
+// an analysis or an update to the run
+type fileRecord struct {
+	Run        *RunRecord `json:",omitempty"`
+	Generation int
+	Analysis   *Analysis `json:",omitempty"`
+}
+
+// fileStorage keeps an append-only log file for each biologist in a directory
+type fileStorage struct { // {{{
+	dir   string
+	mutex sync.Mutex
+}
+
+const fileStorageExtension = ".log"
+
+func (t *fileStorage) path(id []byte) string {
+	return filepath.Join(t.dir, hex.EncodeToString(id)+fileStorageExtension)
+}
+
+func (t *fileStorage) write(id []byte, record fileRecord, flags int) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	file, err := os.OpenFile(t.path(id), flags|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (t *fileStorage) Create(record RunRecord) error {
+	return t.write(record.ID, fileRecord{Run: &record}, os.O_CREATE|os.O_TRUNC)
+}
+
+func (t *fileStorage) Update(record RunRecord) error {
+	return t.write(record.ID, fileRecord{Run: &record}, os.O_APPEND)
+}
+
+func (t *fileStorage) Append(id []byte, generation int, analysis Analysis) error {
+	return t.write(id, fileRecord{Generation: generation, Analysis: &analysis}, os.O_APPEND)
+}
+
+func (t *fileStorage) Open(id []byte) (*RunRecord, []Analysis, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	file, err := os.Open(t.path(id))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var run *RunRecord
+	analyses := make([]Analysis, 0)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var record fileRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// A partially written record at the end of the log is from an interrupted write
+			break
+		}
+
+		switch {
+		case record.Run != nil:
+			run = record.Run
+		case record.Analysis != nil && record.Generation == len(analyses):
+			analyses = append(analyses, *record.Analysis)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if run == nil {
+		return nil, nil, errors.New("log does not begin with a run record")
+	}
+
+	return run, analyses, nil
+}
+
+func (t *fileStorage) Remove(id []byte) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return os.Remove(t.path(id))
+}
+
+func (t *fileStorage) IDs() ([][]byte, error) {
+	files, err := ioutil.ReadDir(t.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([][]byte, 0)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), fileStorageExtension) {
+			continue
+		}
+		if id, err := hex.DecodeString(strings.TrimSuffix(file.Name(), fileStorageExtension)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+} // }}}
+
+// NewFileStorage creates a Storage which keeps a log file for each biologist in the given directory
+func NewFileStorage(dir string) (Storage, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := new(fileStorage)
+	s.dir = dir
+
+	return s, nil
+}
+
+// Reopen recreates the biologist with the given ID from its stored analyses. A biologist whose analysis
+// was still active resumes its simulation from the last stored generation once started.
+// Any further analyses are also stored
+func Reopen(storage Storage, id []byte) (*Biologist, error) {
+	record, analyses, err := storage.Open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := record.Seed
+	var dying []CellState
+	offset := 0
+	if last := len(analyses) - 1; last > 0 && analyses[last].Status == Active {
+		seed = analyses[last].Living
+		dying = analyses[last].Dying
+		offset = last
+	}
+
+	b, err := create(record.ID, record.Dims, Pattern(seed), record.Rules, record.Options)
+	if err != nil {
+		return nil, err
+	}
+	if simulation, ok := b.Life.(multiStateSimulation); ok {
+		simulation.resume(dying)
+	}
+	b.seed = record.Seed
+	b.offset = offset
+	b.storage = storage
+	b.running = record.Running
+
+	// Replay the analyses through the detectors to recover their state
+	for generation := range analyses {
+		analysis := analyses[generation]
+		if analysis.Status == Stable || analysis.Status == Emitting {
+			analysis.Status = Active
+		}
+		b.process(&analysis, generation)
+	}
+
+	if err := b.analyses.Persist(storage, b.ID, len(analyses)); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,172 @@
+package biologist
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func tempStorage(t *testing.T) (Storage, string) {
+	dir, err := ioutil.TempDir("", "biologist")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s\n", err)
+	}
+
+	storage, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("Unable to create storage: %s\n", err)
+	}
+
+	return storage, dir
+}
+
+func TestFileStorage(t *testing.T) {
+	storage, dir := tempStorage(t)
+	defer os.RemoveAll(dir)
+
+	id := []byte{0xbe, 0xef}
+	record := RunRecord{ID: id, Dims: life.Dimensions{Width: 3, Height: 3}, Rules: ConwayRules(), Seed: []life.Location{{X: 1, Y: 1}}}
+	if err := storage.Create(record); err != nil {
+		t.Fatalf("Unable to create record: %s\n", err)
+	}
+
+	for generation := 0; generation < 3; generation++ {
+		analysis := Analysis{Status: Active, Living: []life.Location{{X: generation, Y: 0}}}
+		if err := storage.Append(id, generation, analysis); err != nil {
+			t.Fatalf("Unable to append generation %d: %s\n", generation, err)
+		}
+	}
+
+	// Simulate a write which was interrupted
+	file, err := os.OpenFile(filepath.Join(dir, "beef.log"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Unable to open log: %s\n", err)
+	}
+	file.WriteString(`{"Generation":3,"Analy`)
+	file.Close()
+
+	stored, analyses, err := storage.Open(id)
+	if err != nil {
+		t.Fatalf("Unable to open record: %s\n", err)
+	}
+	if !bytes.Equal(stored.ID, id) || !stored.Dims.Equals(&record.Dims) || len(stored.Seed) != 1 {
+		t.Errorf("Opened unexpected record: %v\n", stored)
+	}
+	if len(analyses) != 3 {
+		t.Fatalf("Expected 3 analyses but found %d\n", len(analyses))
+	}
+	if analyses[2].Living[0].X != 2 {
+		t.Errorf("Analyses were not read in order: %v\n", analyses)
+	}
+
+	ids, err := storage.IDs()
+	if err != nil || len(ids) != 1 || !bytes.Equal(ids[0], id) {
+		t.Errorf("Unexpected stored IDs: %v (%v)\n", ids, err)
+	}
+
+	if err := storage.Remove(id); err != nil {
+		t.Fatalf("Unable to remove record: %s\n", err)
+	}
+	if _, _, err := storage.Open(id); err == nil {
+		t.Error("Opened record after it was removed")
+	}
+}
+
+func TestReopen(t *testing.T) {
+	storage, dir := tempStorage(t)
+	defer os.RemoveAll(dir)
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	if err := biologist.Persist(storage); err != nil {
+		t.Fatalf("Unable to persist biologist: %s\n", err)
+	}
+
+	biologist.Start()
+	<-biologist.Done()
+	biologist.Stop()
+
+	reopened, err := Reopen(storage, biologist.ID)
+	if err != nil {
+		t.Fatalf("Unable to reopen biologist: %s\n", err)
+	}
+
+	if !bytes.Equal(reopened.ID, biologist.ID) {
+		t.Errorf("Reopened biologist has ID %x instead of %x\n", reopened.ID, biologist.ID)
+	}
+	if reopened.analyses.Count() != biologist.analyses.Count() {
+		t.Fatalf("Reopened %d analyses instead of %d\n", reopened.analyses.Count(), biologist.analyses.Count())
+	}
+	if !reopened.stabilityDetector.Detected {
+		t.Fatal("Reopened biologist did not find the stable cycle")
+	}
+
+	generation := biologist.analyses.Count() + 5
+	expected := biologist.Analysis(generation)
+	analysis := reopened.Analysis(generation)
+	if analysis == nil || analysis.Status != Stable || !sameCells(analysis.Living, expected.Living) {
+		t.Fatalf("Reopened biologist has unexpected analysis of generation %d: %v\n", generation, analysis)
+	}
+}
+
+func TestReopenGenerations(t *testing.T) {
+	storage, dir := tempStorage(t)
+	defer os.RemoveAll(dir)
+
+	rules, err := ParseRules("B2/S/3")
+	if err != nil {
+		t.Fatalf("Unable to parse rules: %s\n", err)
+	}
+	size := life.Dimensions{Width: 12, Height: 12}
+	biologist, err := NewWithOptions(size, Pattern([]life.Location{{X: 5, Y: 5}, {X: 6, Y: 5}}), rules, Options{})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	if err := biologist.Persist(storage); err != nil {
+		t.Fatalf("Unable to persist biologist: %s\n", err)
+	}
+	runFor(biologist, 1)
+
+	reopened, err := Reopen(storage, biologist.ID)
+	if err != nil {
+		t.Fatalf("Unable to reopen biologist: %s\n", err)
+	}
+
+	// The simulation resumes with the dying cells of the last stored generation
+	if dying := reopened.Life.(*board).dyingCells(); len(dying) != 2 {
+		t.Errorf("Expected 2 dying cells after reopening but found %d\n", len(dying))
+	}
+}
+
+func TestManagerStorage(t *testing.T) {
+	storage, dir := tempStorage(t)
+	defer os.RemoveAll(dir)
+
+	mgr := NewManager()
+	mgr.SetStorage(storage)
+
+	size := life.Dimensions{Width: 3, Height: 3}
+	biologist, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	mgr.Add(biologist)
+
+	restarted := NewManager()
+	restarted.SetStorage(storage)
+	if reopened := restarted.Biologist(biologist.ID); reopened == nil || reopened.analyses.Count() != 1 {
+		t.Fatal("Could not reopen biologist from storage")
+	}
+
+	mgr.Remove(biologist.ID)
+	if ids, _ := storage.IDs(); len(ids) != 0 {
+		t.Errorf("Biologist remained in storage after it was removed: %v\n", ids)
+	}
+}
@@ -0,0 +1,177 @@
+package biologist
+
+import (
+	"fmt"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// transforms are the rotations and reflections of the square, starting with the identity
+var transforms = [8]func(life.Location) life.Location{
+	func(l life.Location) life.Location { return life.Location{X: l.X, Y: l.Y} },   // identity
+	func(l life.Location) life.Location { return life.Location{X: -l.Y, Y: l.X} },  // rotate 90
+	func(l life.Location) life.Location { return life.Location{X: -l.X, Y: -l.Y} }, // rotate 180
+	func(l life.Location) life.Location { return life.Location{X: l.Y, Y: -l.X} },  // rotate 270
+	func(l life.Location) life.Location { return life.Location{X: -l.X, Y: l.Y} },  // mirror across vertical axis
+	func(l life.Location) life.Location { return life.Location{X: l.X, Y: -l.Y} },  // mirror across horizontal axis
+	func(l life.Location) life.Location { return life.Location{X: l.Y, Y: l.X} },   // mirror across diagonal
+	func(l life.Location) life.Location { return life.Location{X: -l.Y, Y: -l.X} }, // mirror across anti-diagonal
+}
+
+const (
+	transformRotate90 = iota + 1
+	transformRotate180
+	transformRotate270
+	transformMirrorVertical
+	transformMirrorHorizontal
+	transformMirrorDiagonal
+	transformMirrorAntiDiagonal
+)
+
+// allTransforms indexes every one of the transforms
+var allTransforms = []int{0, transformRotate90, transformRotate180, transformRotate270,
+	transformMirrorVertical, transformMirrorHorizontal, transformMirrorDiagonal, transformMirrorAntiDiagonal}
+
+// transformCells applies the indicated transform and then translates the cells so that their top-left corner is at the origin
+func transformCells(cells []life.Location, transform int) []life.Location {
+	transformed := make([]life.Location, len(cells))
+	for i, loc := range cells {
+		transformed[i] = transforms[transform](loc)
+	}
+
+	min, _ := bounds(transformed)
+	for i := range transformed {
+		transformed[i].X -= min.X
+		transformed[i].Y -= min.Y
+	}
+
+	return transformed
+}
+
+type symmetry int // {{{
+
+const (
+	// C1 applies to a pattern which has no symmetry
+	C1 symmetry = iota
+	// C2 applies to a pattern which is unchanged by a 180 degree rotation
+	C2
+	// C4 applies to a pattern which is unchanged by a 90 degree rotation
+	C4
+	// D2Orthogonal applies to a pattern with a single horizontal or vertical mirror
+	D2Orthogonal
+	// D2Diagonal applies to a pattern with a single diagonal mirror
+	D2Diagonal
+	// D4Orthogonal applies to a pattern with both a horizontal and a vertical mirror
+	D4Orthogonal
+	// D4Diagonal applies to a pattern with mirrors across both diagonals
+	D4Diagonal
+	// D8 applies to a pattern which is unchanged by any rotation or reflection
+	D8
+	// NoSymmetry applies to a generation without any living cells, which has no pattern to be symmetric
+	NoSymmetry
+)
+
+func (t symmetry) String() string {
+	switch t {
+	case C1:
+		return "C1"
+	case C2:
+		return "C2"
+	case C4:
+		return "C4"
+	case D2Orthogonal:
+		return "D2+"
+	case D2Diagonal:
+		return "D2x"
+	case D4Orthogonal:
+		return "D4+"
+	case D4Diagonal:
+		return "D4x"
+	case D8:
+		return "D8"
+	case NoSymmetry:
+		return "None"
+	}
+
+	return "Unknown"
+}
+
+// order returns the number of transforms which leave a pattern with this symmetry unchanged
+func (t symmetry) order() int {
+	switch t {
+	case C2, D2Orthogonal, D2Diagonal:
+		return 2
+	case C4, D4Orthogonal, D4Diagonal:
+		return 4
+	case D8:
+		return 8
+	case NoSymmetry:
+		return 0
+	}
+
+	return 1
+} // }}}
+
+// symmetryOf determines the symmetry group of the given cells
+func symmetryOf(cells []life.Location) symmetry {
+	if len(cells) == 0 {
+		return NoSymmetry
+	}
+
+	original := make(map[life.Location]bool, len(cells))
+	for _, loc := range transformCells(cells, 0) {
+		original[loc] = true
+	}
+
+	var invariant [len(transforms)]bool
+	for transform := 1; transform < len(transforms); transform++ {
+		invariant[transform] = true
+		for _, loc := range transformCells(cells, transform) {
+			if !original[loc] {
+				invariant[transform] = false
+				break
+			}
+		}
+	}
+
+	mirrored := invariant[transformMirrorVertical] || invariant[transformMirrorHorizontal] ||
+		invariant[transformMirrorDiagonal] || invariant[transformMirrorAntiDiagonal]
+
+	switch {
+	case invariant[transformRotate90] && mirrored:
+		return D8
+	case invariant[transformRotate90]:
+		return C4
+	case invariant[transformMirrorVertical] && invariant[transformMirrorHorizontal]:
+		return D4Orthogonal
+	case invariant[transformMirrorDiagonal] && invariant[transformMirrorAntiDiagonal]:
+		return D4Diagonal
+	case invariant[transformRotate180]:
+		return C2
+	case invariant[transformMirrorVertical] || invariant[transformMirrorHorizontal]:
+		return D2Orthogonal
+	case invariant[transformMirrorDiagonal] || invariant[transformMirrorAntiDiagonal]:
+		return D2Diagonal
+	}
+
+	return C1
+}
+
+// SymmetryChange records a generation in which the symmetry of the living cells changed
+type SymmetryChange struct { // {{{
+	Generation int
+	From       symmetry
+	To         symmetry
+}
+
+// Broken is true when the new symmetry is preserved by fewer transforms than the previous one.
+// Dying out does not break the symmetry
+func (t *SymmetryChange) Broken() bool {
+	return t.To != NoSymmetry && t.To.order() < t.From.order()
+}
+
+func (t *SymmetryChange) String() string {
+	return fmt.Sprintf("{%d: %s -> %s}", t.Generation, t.From.String(), t.To.String())
+} // }}}
+
+// vim: set foldmethod=marker:
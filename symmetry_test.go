@@ -0,0 +1,48 @@
+package biologist
+
+import (
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestSymmetryOf(t *testing.T) {
+	patterns := []struct {
+		name     string
+		cells    []life.Location
+		expected symmetry
+	}{
+		{"block", []life.Location{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}, D8},
+		{"blinker", []life.Location{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}, D4Orthogonal},
+		{"diagonal pair", []life.Location{{X: 0, Y: 0}, {X: 1, Y: 1}}, D4Diagonal},
+		{"pinwheel", []life.Location{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 1, Y: 0}, {X: 3, Y: 1}, {X: 2, Y: 3}, {X: 0, Y: 2}}, C4},
+		{"z-tetromino", []life.Location{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}}, C2},
+		{"t-tetromino", []life.Location{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}}, D2Orthogonal},
+		{"l-tromino", []life.Location{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}, D2Diagonal},
+		{"r-pentomino", []life.Location{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}}, C1},
+		{"empty", []life.Location{}, NoSymmetry},
+	}
+
+	for _, pattern := range patterns {
+		if found := symmetryOf(pattern.cells); found != pattern.expected {
+			t.Errorf("Expected %s to have %s symmetry but found %s\n", pattern.name, pattern.expected.String(), found.String())
+		}
+	}
+}
+
+func TestSymmetryChangeBroken(t *testing.T) {
+	broken := SymmetryChange{Generation: 1, From: D8, To: C2}
+	if !broken.Broken() {
+		t.Errorf("Expected %s to be broken\n", broken.String())
+	}
+
+	died := SymmetryChange{Generation: 1, From: D8, To: NoSymmetry}
+	if died.Broken() {
+		t.Errorf("Did not expect %s to be broken\n", died.String())
+	}
+
+	gained := SymmetryChange{Generation: 1, From: C1, To: D2Orthogonal}
+	if gained.Broken() {
+		t.Errorf("Did not expect %s to be broken\n", gained.String())
+	}
+}
@@ -0,0 +1,163 @@
+package biologist
+
+import (
+	"fmt"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+// Topology determines what lies beyond the edges of the board
+type Topology int // {{{
+
+const (
+	// EngineTopology leaves the edges up to the life engine
+	EngineTopology Topology = iota
+	// Bounded surrounds the board with walls of dead cells
+	Bounded
+	// Torus joins the left edge to the right edge and the top edge to the bottom edge
+	Torus
+	// KleinBottle joins the left edge to the right edge and the top edge to the bottom edge with a twist
+	KleinBottle
+	// CrossSurface joins both pairs of opposite edges with a twist
+	CrossSurface
+	// Unbounded has no edges, so cells evolve as on an infinite plane and the dimensions only frame the seed
+	Unbounded
+)
+
+func (t Topology) String() string {
+	switch t {
+	case EngineTopology:
+		return "Engine"
+	case Bounded:
+		return "Bounded"
+	case Torus:
+		return "Torus"
+	case KleinBottle:
+		return "KleinBottle"
+	case CrossSurface:
+		return "CrossSurface"
+	case Unbounded:
+		return "Unbounded"
+	}
+
+	return "Unknown"
+}
+
+// ParseTopology returns the topology with the given name, or the engine's topology if the name is empty
+func ParseTopology(name string) (Topology, error) {
+	if name == "" {
+		return EngineTopology, nil
+	}
+	for topology := EngineTopology; topology <= Unbounded; topology++ {
+		if topology.String() == name {
+			return topology, nil
+		}
+	}
+	return EngineTopology, fmt.Errorf("unknown topology: %s", name)
+}
+
+// wrapped returns true if cells which move off an edge come back on another one
+func (t Topology) wrapped() bool {
+	return t == Torus || t == KleinBottle || t == CrossSurface
+}
+
+// translatable returns true if cells evolve alike wherever they are placed on the board
+func (t Topology) translatable() bool {
+	return t == Torus || t == Unbounded
+}
+
+// place puts the seed on the board as it is seeded, wrapping cells beyond the edges around or dropping them
+func (t Topology) place(dims life.Dimensions, seed []life.Location) []life.Location {
+	found := make(map[life.Location]bool)
+	placed := make([]life.Location, 0, len(seed))
+	for _, loc := range seed {
+		if wrapped, onBoard := t.wrap(dims, loc); onBoard && !found[wrapped] {
+			found[wrapped] = true
+			placed = append(placed, wrapped)
+		}
+	}
+	return placed
+}
+
+// symmetries lists the transforms which map a board of the given size with this topology onto itself
+func (t Topology) symmetries(dims life.Dimensions) []int {
+	// Quarter turns and diagonal reflections swap the edges, so both pairs of edges must be alike
+	if t == Unbounded || (t != KleinBottle && dims.Width == dims.Height) {
+		return allTransforms
+	}
+	return []int{0, transformRotate180, transformMirrorVertical, transformMirrorHorizontal}
+}
+
+// wrap maps the location onto the board, returning false if it falls off the board
+func (t Topology) wrap(dims life.Dimensions, loc life.Location) (life.Location, bool) {
+	if t == Unbounded {
+		return loc, true
+	}
+
+	inX := loc.X >= 0 && loc.X < dims.Width
+	inY := loc.Y >= 0 && loc.Y < dims.Height
+	if inX && inY {
+		return loc, true
+	}
+
+	switch t {
+	case Torus:
+		return life.Location{X: mod(loc.X, dims.Width), Y: mod(loc.Y, dims.Height)}, true
+	case KleinBottle:
+		// Crossing the top or bottom edge mirrors the columns
+		if !inY {
+			loc.X = dims.Width - 1 - loc.X
+		}
+		return life.Location{X: mod(loc.X, dims.Width), Y: mod(loc.Y, dims.Height)}, true
+	case CrossSurface:
+		// Crossing the top or bottom edge mirrors the columns and crossing the sides mirrors the rows
+		if !inY {
+			loc.X = dims.Width - 1 - loc.X
+		}
+		if !inX {
+			loc.Y = dims.Height - 1 - loc.Y
+		}
+		return life.Location{X: mod(loc.X, dims.Width), Y: mod(loc.Y, dims.Height)}, true
+	}
+
+	return loc, false
+} // }}}
+
+func mod(val, divisor int) int {
+	val %= divisor
+	if val < 0 {
+		val += divisor
+	}
+	return val
+}
+
+// Options selects how the board of a simulation behaves
+type Options struct { // {{{
+	Topology Topology
+}
+
+func (t *Options) String() string {
+	return t.Topology.String()
+} // }}}
+
+// geometry describes how the cells of a board are connected to each other
+type geometry struct { // {{{
+	dims     life.Dimensions
+	topology Topology
+	offsets  []life.Location
+}
+
+// move returns the cell reached by moving from the location by the offset
+func (t *geometry) move(loc life.Location, dx, dy int) (life.Location, bool) {
+	moved := life.Location{X: loc.X + dx, Y: loc.Y + dy}
+	if !t.topology.wrapped() {
+		// No cells live beyond the edges, so there is no need to check them
+		return moved, true
+	}
+	return t.topology.wrap(t.dims, moved)
+} // }}}
+
+// planeGeometry connects cells as though they were on an unbounded plane
+var planeGeometry = geometry{topology: Bounded, offsets: mooreOffsets}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,195 @@
+package biologist
+
+import (
+	"os"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestTopologyWrap(t *testing.T) {
+	dims := life.Dimensions{Width: 4, Height: 3}
+	tests := []struct {
+		topology Topology
+		loc      life.Location
+		expected life.Location
+		onBoard  bool
+	}{
+		{Bounded, life.Location{X: 1, Y: 1}, life.Location{X: 1, Y: 1}, true},
+		{Bounded, life.Location{X: -1, Y: 1}, life.Location{X: -1, Y: 1}, false},
+		{Torus, life.Location{X: -1, Y: 3}, life.Location{X: 3, Y: 0}, true},
+		{Torus, life.Location{X: 4, Y: -1}, life.Location{X: 0, Y: 2}, true},
+		{KleinBottle, life.Location{X: -1, Y: 1}, life.Location{X: 3, Y: 1}, true},
+		{KleinBottle, life.Location{X: 0, Y: -1}, life.Location{X: 3, Y: 2}, true},
+		{CrossSurface, life.Location{X: -1, Y: 0}, life.Location{X: 3, Y: 2}, true},
+		{CrossSurface, life.Location{X: 1, Y: 3}, life.Location{X: 2, Y: 0}, true},
+	}
+
+	for _, test := range tests {
+		wrapped, onBoard := test.topology.wrap(dims, test.loc)
+		if onBoard != test.onBoard || (onBoard && !wrapped.Equals(&test.expected)) {
+			t.Errorf("%s moved %s to %s (%t) instead of %s\n", test.topology.String(), test.loc.String(), wrapped.String(), onBoard, test.expected.String())
+		}
+	}
+}
+
+func TestParseTopology(t *testing.T) {
+	for topology := EngineTopology; topology <= Unbounded; topology++ {
+		if parsed, err := ParseTopology(topology.String()); err != nil || parsed != topology {
+			t.Errorf("Could not parse %s\n", topology.String())
+		}
+	}
+	if topology, err := ParseTopology(""); err != nil || topology != EngineTopology {
+		t.Error("Empty topology should default to the engine")
+	}
+	if _, err := ParseTopology("Sphere"); err == nil {
+		t.Error("Parsed an unknown topology")
+	}
+}
+
+func TestTorusGlider(t *testing.T) {
+	// A glider on a torus keeps flying until it comes back around to where it started
+	size := life.Dimensions{Width: 8, Height: 8}
+	biologist, err := NewWithOptions(size, life.Gliders, ConwayRules(), Options{Topology: Torus})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	biologist.Start()
+	<-biologist.Done()
+
+	if !biologist.stabilityDetector.Detected || biologist.stabilityDetector.CycleLength != 32 {
+		t.Fatalf("Expected a cycle of 32 generations but found %d\n", biologist.stabilityDetector.CycleLength)
+	}
+
+	// The glider is still recognized as it crosses the edges
+	for generation := 0; generation < 32; generation++ {
+		census := biologist.Census(generation)
+		if len(census.Objects) != 1 || census.Objects[0].Label != "glider" {
+			t.Fatalf("Glider was not recognized in generation %d: %s\n", generation, census.String())
+		}
+	}
+}
+
+func TestBoundedGlider(t *testing.T) {
+	size := life.Dimensions{Width: 8, Height: 8}
+	biologist, err := NewWithOptions(size, life.Gliders, ConwayRules(), Options{Topology: Bounded})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	biologist.Start()
+	<-biologist.Done()
+
+	// The glider crashes into the corner and becomes a block
+	census := biologist.Census(biologist.analyses.Count() - 1)
+	if len(census.Objects) != 1 || census.Objects[0].Label != "block" {
+		t.Errorf("Expected the glider to become a block but found %s\n", census.String())
+	}
+}
+
+func TestCensusAcrossEdges(t *testing.T) {
+	dims := life.Dimensions{Width: 6, Height: 6}
+	corners := []life.Location{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 0, Y: 5}, {X: 5, Y: 5}}
+
+	if census := takeCensusOn(corners, geometry{dims: dims, topology: Torus, offsets: mooreOffsets}); len(census.Objects) != 1 || census.Objects[0].Label != "block" {
+		t.Errorf("Block across the corners of a torus was not recognized: %s\n", census.String())
+	}
+	if census := takeCensusOn(corners, geometry{dims: dims, topology: Bounded, offsets: mooreOffsets}); len(census.Objects) != 4 {
+		t.Errorf("Expected 4 objects on a bounded board but found %d\n", len(census.Objects))
+	}
+}
+
+func TestReopenTopology(t *testing.T) {
+	storage, dir := tempStorage(t)
+	defer os.RemoveAll(dir)
+
+	size := life.Dimensions{Width: 8, Height: 8}
+	biologist, err := NewWithOptions(size, life.Gliders, ConwayRules(), Options{Topology: KleinBottle})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	if err := biologist.Persist(storage); err != nil {
+		t.Fatalf("Unable to persist biologist: %s\n", err)
+	}
+
+	reopened, err := Reopen(storage, biologist.ID)
+	if err != nil {
+		t.Fatalf("Unable to reopen biologist: %s\n", err)
+	}
+	if reopened.Options().Topology != KleinBottle {
+		t.Errorf("Reopened biologist is on a %s\n", reopened.Options().Topology.String())
+	}
+}
+
+func TestUnboundedGlider(t *testing.T) {
+	// Without any walls the glider flies off beyond the frame of the seed
+	size := life.Dimensions{Width: 8, Height: 8}
+	biologist, err := NewWithOptions(size, life.Gliders, ConwayRules(), Options{Topology: Unbounded})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	runFor(biologist, 40)
+
+	analysis := biologist.Analysis(40)
+	if analysis == nil || analysis.Status != Active {
+		t.Fatalf("Glider stopped flying: %v\n", analysis)
+	}
+	census := biologist.Census(40)
+	if len(census.Objects) != 1 || census.Objects[0].Label != "glider" {
+		t.Fatalf("Glider was not recognized: %s\n", census.String())
+	}
+
+	min, max := biologist.Bounds()
+	cellMin, cellMax := bounds(analysis.Living)
+	if cellMin.X < 8 && cellMin.Y < 8 && cellMax.X < 8 && cellMax.Y < 8 {
+		t.Errorf("Glider is still within the frame of the seed: %v\n", analysis.Living)
+	}
+	if cellMin.X < min.X || cellMin.Y < min.Y || cellMax.X > max.X || cellMax.Y > max.Y {
+		t.Errorf("Bounds %s - %s do not contain the glider\n", min.String(), max.String())
+	}
+}
+
+func TestUnboundedExtent(t *testing.T) {
+	size := life.Dimensions{Width: 8, Height: 8}
+	biologist, err := NewWithOptions(size, life.Gliders, ConwayRules(), Options{Topology: Unbounded})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	runFor(biologist, 40)
+
+	_, dims := biologist.extent()
+	if dims.Width <= size.Width && dims.Height <= size.Height {
+		t.Fatalf("Extent %s did not grow beyond the frame of the seed\n", dims.String())
+	}
+
+	// Every living cell of every generation is counted, including those beyond the frame
+	heatmap := biologist.Heatmap()
+	if heatmap.Dims.Width != dims.Width || heatmap.Dims.Height != dims.Height {
+		t.Errorf("Heatmap of %s does not cover the extent %s\n", heatmap.Dims.String(), dims.String())
+	}
+	counted, living := 0, 0
+	for _, row := range heatmap.Alive {
+		for _, count := range row {
+			counted += count
+		}
+	}
+	for generation := 0; generation < heatmap.Generations; generation++ {
+		living += len(biologist.Analysis(generation).Living)
+	}
+	if counted != living {
+		t.Errorf("Heatmap counted %d of %d living cells\n", counted, living)
+	}
+
+	if class := biologist.WolframClass(); class == Homogeneous {
+		t.Errorf("Glider was classified as %s\n", class.String())
+	}
+}
+
+func TestUnboundedBirthWithoutNeighbors(t *testing.T) {
+	rules, err := ParseRules("B0/S8")
+	if err != nil {
+		t.Fatalf("Unable to parse rules: %s\n", err)
+	}
+	if _, err := NewWithOptions(life.Dimensions{Width: 8, Height: 8}, life.Gliders, rules, Options{Topology: Unbounded}); err == nil {
+		t.Error("Created an unbounded board which cells are born on without neighbors")
+	}
+}
@@ -0,0 +1,96 @@
+package biologist
+
+const (
+	// Number of generations a run needs to have been analyzed before it can be called chaotic or complex
+	wolframMinGenerations = 100
+	// Number of most recent generations whose block entropy is looked at
+	wolframWindow = 50
+	// Fraction of the living cells which need to be part of known objects for a run to be considered complex
+	wolframMinKnownFraction = 0.5
+	// Drop in block entropy across the window, relative to its mean, which shows structure emerging
+	wolframOrdering = -0.1
+)
+
+type wolframClass int // {{{
+
+const (
+	// WolframUndetermined applies to a run which has not been analyzed long enough to classify
+	WolframUndetermined wolframClass = iota
+	// Homogeneous applies to a run which settles into a uniform state (Wolfram class 1)
+	Homogeneous
+	// Periodic applies to a run which settles into still lifes and oscillators (Wolfram class 2)
+	Periodic
+	// Chaotic applies to a run which stays disordered (Wolfram class 3)
+	Chaotic
+	// Complex applies to a run where localized structures keep interacting (Wolfram class 4)
+	Complex
+)
+
+func (t wolframClass) String() string {
+	switch t {
+	case WolframUndetermined:
+		return "Undetermined"
+	case Homogeneous:
+		return "Homogeneous"
+	case Periodic:
+		return "Periodic"
+	case Chaotic:
+		return "Chaotic"
+	case Complex:
+		return "Complex"
+	}
+
+	return "Unknown"
+} // }}}
+
+// WolframClass coarsely labels the behavior of the run using Wolfram's classes of cellular automata
+func (t *Biologist) WolframClass() wolframClass {
+	count := t.analyses.Count()
+	if count == 0 {
+		return WolframUndetermined
+	}
+
+	_, dims := t.extent()
+	last := t.analyses.Get(count - 1)
+	lastDensity := density(len(last.Living), dims.Width*dims.Height)
+
+	detected, _, _ := t.cycle()
+	switch {
+	case last.Status == Dead:
+		return Homogeneous
+	case detected:
+		if lastDensity >= 1 {
+			return Homogeneous
+		}
+		return Periodic
+	case last.Status == Emitting:
+		// Guns and puffers are the archetypal interacting structures
+		return Complex
+	case count < wolframMinGenerations:
+		return WolframUndetermined
+	}
+
+	// Structure emerging from disorder shows up as the block entropy falling
+	entropies := make([]float64, 0)
+	for generation := count - wolframWindow; generation < count; generation++ {
+		analysis := t.analyses.Get(generation)
+		entropies = append(entropies, analysis.Complexity.BlockEntropy)
+	}
+	ordering := relativeChange(entropies)
+
+	census := t.census(last.Living)
+	known := 0
+	for _, object := range census.Objects {
+		if object.Label != "" {
+			known += len(object.Cells)
+		}
+	}
+	knownFraction := density(known, census.Population)
+
+	if knownFraction >= wolframMinKnownFraction || ordering <= wolframOrdering {
+		return Complex
+	}
+	return Chaotic
+}
+
+// vim: set foldmethod=marker:
@@ -0,0 +1,90 @@
+package biologist
+
+import (
+	"math/rand"
+	"testing"
+
+	"gitlab.com/hokiegeek/life"
+)
+
+func TestWolframClassFinished(t *testing.T) {
+	size := life.Dimensions{Width: 3, Height: 3}
+
+	blinker, err := New(size, life.Blinkers, life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	if class := blinker.WolframClass(); class != WolframUndetermined {
+		t.Errorf("Seed was classified as %s before it was run\n", class.String())
+	}
+	blinker.Start()
+	<-blinker.Done()
+	if class := blinker.WolframClass(); class != Periodic {
+		t.Errorf("Blinker was classified as %s\n", class.String())
+	}
+
+	lonely, err := New(size, Pattern([]life.Location{{X: 1, Y: 1}}), life.ConwayTester())
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+	lonely.Start()
+	<-lonely.Done()
+	if class := lonely.WolframClass(); class != Homogeneous {
+		t.Errorf("Dying seed was classified as %s\n", class.String())
+	}
+}
+
+// replay runs the generations through the detectors as though they had been simulated
+func replay(t *testing.T, generations [][]life.Location) *Biologist {
+	dims := life.Dimensions{Width: 32, Height: 32}
+	biologist, err := create(uniqueID(), dims, Pattern(generations[0]), ConwayRules(), Options{})
+	if err != nil {
+		t.Fatalf("Unable to create biologist: %s\n", err)
+	}
+
+	for generation, living := range generations {
+		analysis := Analysis{Status: Active, Living: living, Complexity: complexityOf(life.Location{}, dims, living)}
+		biologist.process(&analysis, generation)
+	}
+
+	return biologist
+}
+
+func TestWolframClassChaotic(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	generations := make([][]life.Location, 0)
+	for generation := 0; generation < wolframMinGenerations; generation++ {
+		generations = append(generations, soup(life.Dimensions{Width: 32, Height: 32}, 50, random))
+	}
+
+	if class := replay(t, generations).WolframClass(); class != Chaotic {
+		t.Errorf("Noise was classified as %s\n", class.String())
+	}
+}
+
+func TestWolframClassComplex(t *testing.T) {
+	// A row of blocks which drifts across the board without ever repeating
+	generations := make([][]life.Location, 0)
+	for generation := 0; generation < wolframMinGenerations; generation++ {
+		living := make([]life.Location, 0)
+		for block := 0; block < 5; block++ {
+			x := block * 4
+			y := generation % 30
+			if generation >= 30 {
+				x++
+			}
+			if generation >= 60 {
+				x++
+			}
+			if generation >= 90 {
+				x++
+			}
+			living = append(living, life.Location{X: x, Y: y}, life.Location{X: x + 1, Y: y}, life.Location{X: x, Y: y + 1}, life.Location{X: x + 1, Y: y + 1})
+		}
+		generations = append(generations, living)
+	}
+
+	if class := replay(t, generations).WolframClass(); class != Complex {
+		t.Errorf("Blocks were classified as %s\n", class.String())
+	}
+}